@@ -1,6 +1,8 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
@@ -9,6 +11,7 @@ import (
 	"cultpedia/internal/checks"
 	"cultpedia/internal/ui"
 	"cultpedia/internal/utils"
+	"cultpedia/pkg/manifest"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -36,27 +39,20 @@ func handleCommand(cmd string, args []string) {
 		utils.PrintHelp()
 		os.Exit(0)
 	case "validate":
-		err := checks.ValidateQuestions()
-		if err != nil {
-			fmt.Println("✗ Validation Failed:")
-			fmt.Println()
-			fmt.Println(err)
-			os.Exit(1)
-		} else {
-			fmt.Println("✔ Validation Successful - All questions are valid!")
-		}
+		runReport(cmd, args, checks.ValidateQuestions, "Validation Failed", "Validation Successful - All questions are valid!")
 	case "check-duplicates":
-		result := checks.CheckDuplicates()
-		fmt.Println(result)
-		if strings.Contains(result, "detected") {
-			os.Exit(1)
-		}
+		runReport(cmd, args, checks.CheckDuplicates, "Duplicates detected", "No duplicates.")
 	case "check-translations":
-		result := checks.CheckTranslations()
-		fmt.Println(result)
-		if strings.Contains(result, "missing") {
+		runReport(cmd, args, checks.CheckTranslations, "Missing translations", "All translations present.")
+	case "check-similar-questions":
+		fs, format := newReportFlags(cmd)
+		threshold := fs.Float64("threshold", checks.DefaultSimilarityThreshold, "minimum similarity score reported (0-1)")
+		_ = fs.Parse(args)
+		if *threshold <= 0 || *threshold > 1 {
+			fmt.Printf("error: threshold must be between 0 and 1 (got %g)\n", *threshold)
 			os.Exit(1)
 		}
+		printReport(checks.CheckSimilarQuestions(*threshold), *format, "Similar questions detected", "No similar questions.")
 	case "add":
 		question, err := actions.ValidateNewQuestion()
 		if err != nil {
@@ -77,41 +73,109 @@ func handleCommand(cmd string, args []string) {
 			os.Exit(1)
 		}
 	case "bump-version":
-		version, err := actions.BumpVersion()
+		fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+		sign := fs.Bool("sign", false, "sign the manifest with the ed25519 key in $"+utils.SigningKeyEnv)
+		_ = fs.Parse(args)
+
+		version, err := actions.BumpVersion(signingKey(*sign))
 		if err != nil {
 			fmt.Printf("error: %v\n", err)
 			os.Exit(1)
 		}
 		fmt.Println(version)
 	case "validate-geography":
-		err := checks.ValidateGeography()
+		fs, format := newReportFlags(cmd)
+		tolerance := fs.Float64("tolerance", checks.DefaultAggregateTolerance, "allowed relative difference between continent totals and the sum of their countries")
+		_ = fs.Parse(args)
+		if *tolerance < 0 {
+			fmt.Printf("error: tolerance must be positive (got %g)\n", *tolerance)
+			os.Exit(1)
+		}
+		printReport(checks.ValidateGeography(*tolerance), *format, "Geography Validation Failed", "Geography Validation Successful - All data is valid!")
+	case "check-geography-duplicates":
+		runReport(cmd, args, checks.CheckGeographyDuplicates, "Duplicates detected", "No duplicates detected in geography dataset")
+	case "check-geography-translations":
+		runReport(cmd, args, checks.CheckGeographyTranslations, "Missing translations", "All geography translations present")
+	case "bump-geography-version":
+		fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+		fix := fs.Bool("fix", false, "recompute continent population and area from their countries, except continents listing transcontinental countries")
+		sign := fs.Bool("sign", false, "sign the manifest with the ed25519 key in $"+utils.SigningKeyEnv)
+		_ = fs.Parse(args)
+
+		version, err := actions.BumpGeographyVersion(*fix, signingKey(*sign))
 		if err != nil {
-			fmt.Println("✗ Geography Validation Failed:")
+			fmt.Printf("error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(version)
+	case "generate-geography-questions":
+		fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+		kind := fs.String("kind", "", "comma-separated question kinds (capital, flag, population, neighbor, population_estimate, area)")
+		count := fs.Int("count", 0, "number of questions to generate (0 for all)")
+		seed := fs.Int64("seed", 1, "random seed")
+		output := fs.String("output", "", "NDJSON file to write (default stdout)")
+		_ = fs.Parse(args)
+
+		kinds, err := actions.ParseGeoQuestionKinds(*kind)
+		if err != nil {
+			fmt.Printf("error: %v\n", err)
+			os.Exit(1)
+		}
+		questions, err := actions.GenerateGeographyQuestions(kinds, *count, *seed)
+		if err != nil {
+			fmt.Printf("error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := checks.ValidateQuestionSet(questions); err != nil {
+			fmt.Println("✗ Generated questions are invalid:")
 			fmt.Println()
 			fmt.Println(err)
 			os.Exit(1)
-		} else {
-			fmt.Println("✔ Geography Validation Successful - All data is valid!")
 		}
-	case "check-geography-duplicates":
-		result := checks.CheckGeographyDuplicates()
-		fmt.Println(result)
-		if strings.HasPrefix(result, "✗") {
-			os.Exit(1)
+
+		out := os.Stdout
+		if *output != "" {
+			out, err = os.Create(*output)
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				os.Exit(1)
+			}
+			defer func() { _ = out.Close() }()
 		}
-	case "check-geography-translations":
-		result := checks.CheckGeographyTranslations()
-		fmt.Println(result)
-		if strings.HasPrefix(result, "✗") {
+		if err := actions.WriteQuestions(out, questions); err != nil {
+			fmt.Printf("error: %v\n", err)
 			os.Exit(1)
 		}
-	case "bump-geography-version":
-		version, err := actions.BumpGeographyVersion()
+		if *output != "" {
+			fmt.Printf("✔ %d geography questions written to %s\n", len(questions), *output)
+		}
+	case "verify":
+		fs, format := newReportFlags(cmd)
+		publicKey := fs.String("public-key", "", "base64 ed25519 public key the manifest signature must match")
+		_ = fs.Parse(args)
+		locations := fs.Args()
+		if len(locations) == 0 {
+			locations = []string{utils.ManifestFile, utils.GeographyManifestFile}
+		}
+
+		var key ed25519.PublicKey
+		if *publicKey != "" {
+			var err error
+			key, err = manifest.ParsePublicKey(*publicKey)
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		printReport(checks.VerifyManifests(key, locations...), *format, "Manifest verification failed", "All files match their manifest.")
+	case "generate-signing-key":
+		publicKey, privateKey, err := manifest.GenerateKey()
 		if err != nil {
 			fmt.Printf("error: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println(version)
+		fmt.Printf("Private key (keep secret, store it in $%s):\n  %s\n\n", utils.SigningKeyEnv, privateKey)
+		fmt.Printf("Public key (pin it in importers, pass it to verify --public-key):\n  %s\n", publicKey)
 	case "init":
 		defaultDir := "new-cultpedia-dataset"
 		datasetName := "new-cultpedia-dataset"
@@ -141,3 +205,59 @@ func handleCommand(cmd string, args []string) {
 		os.Exit(1)
 	}
 }
+
+func runReport(cmd string, args []string, check func() *checks.Report, failure, success string) {
+	fs, format := newReportFlags(cmd)
+	_ = fs.Parse(args)
+	printReport(check(), *format, failure, success)
+}
+
+func newReportFlags(cmd string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	format := fs.String("format", checks.FormatText, "output format ("+strings.Join(checks.ReportFormats, ", ")+")")
+	return fs, format
+}
+
+func printReport(report *checks.Report, format, failure, success string) {
+	if !utils.Contains(checks.ReportFormats, format) {
+		fmt.Printf("error: unknown format '%s' (expected one of: %s)\n", format, strings.Join(checks.ReportFormats, ", "))
+		os.Exit(1)
+	}
+
+	if format == checks.FormatText && report.HasErrors() {
+		fmt.Println("✗ " + failure + ":")
+		fmt.Println()
+	}
+	if err := report.Write(os.Stdout, format); err != nil {
+		fmt.Printf("error: %v\n", err)
+		os.Exit(1)
+	}
+	if format == checks.FormatText {
+		switch warnings := report.Count(checks.SeverityWarning); {
+		case len(report.Findings) == 0:
+			fmt.Println("✔ " + success)
+		case !report.HasErrors():
+			fmt.Printf("No errors, %d warning(s) to review.\n", warnings)
+		}
+	}
+	if report.HasErrors() {
+		os.Exit(1)
+	}
+}
+
+func signingKey(sign bool) ed25519.PrivateKey {
+	if !sign {
+		return nil
+	}
+	value := os.Getenv(utils.SigningKeyEnv)
+	if value == "" {
+		fmt.Printf("error: --sign needs the private key in $%s\n", utils.SigningKeyEnv)
+		os.Exit(1)
+	}
+	key, err := manifest.ParsePrivateKey(value)
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		os.Exit(1)
+	}
+	return key
+}
@@ -1,6 +1,8 @@
 package actions
 
 import (
+	"bytes"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -8,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +19,7 @@ import (
 	"cultpedia/internal/checks"
 	"cultpedia/internal/models"
 	"cultpedia/internal/utils"
+	"cultpedia/pkg/manifest"
 )
 
 func ValidateNewQuestion() (models.Question, error) {
@@ -28,6 +33,18 @@ func ValidateNewQuestionWithType(forceType string) (models.Question, error) {
 	if forceType == "true_false" {
 		jsonFilePath = utils.NewQuestionTrueFalseFile
 		questionType = "true_false"
+	} else if forceType == "numeric" {
+		jsonFilePath = utils.NewQuestionNumericFile
+		questionType = "numeric"
+	} else if forceType == "text_input" {
+		jsonFilePath = utils.NewQuestionTextInputFile
+		questionType = "text_input"
+	} else if forceType == "ordering" {
+		jsonFilePath = utils.NewQuestionOrderingFile
+		questionType = "ordering"
+	} else if forceType == "multiple_choice" {
+		jsonFilePath = utils.NewQuestionMultipleChoiceFile
+		questionType = "multiple_choice"
 	} else if forceType == "single_choice" {
 		jsonFilePath = utils.NewQuestionFile
 		questionType = "single_choice"
@@ -78,6 +95,22 @@ func ValidateNewQuestionWithType(forceType string) (models.Question, error) {
 		if !hasTrue || !hasFalse {
 			return models.Question{}, fmt.Errorf("true_false questions must have answers with slugs 'true' and 'false'")
 		}
+	} else if questionType == "multiple_choice" || question.Qtype == "multiple_choice" {
+		if len(question.Answers) < 2 || len(question.Answers) > 6 {
+			return models.Question{}, fmt.Errorf("multiple_choice questions must have between 2 and 6 answers")
+		}
+	} else if questionType == "ordering" || question.Qtype == "ordering" {
+		if len(question.Answers) < 3 || len(question.Answers) > 6 {
+			return models.Question{}, fmt.Errorf("ordering questions must have between 3 and 6 answers")
+		}
+	} else if questionType == "numeric" || question.Qtype == "numeric" {
+		if len(question.Answers) != 0 || question.Numeric == nil {
+			return models.Question{}, fmt.Errorf("numeric questions must have an empty answers list and a numeric answer")
+		}
+	} else if questionType == "text_input" || question.Qtype == "text_input" {
+		if len(question.Answers) < 1 || len(question.Answers) > 6 {
+			return models.Question{}, fmt.Errorf("text_input questions must have between 1 and 6 answers")
+		}
 	} else {
 		if len(question.Answers) != 4 {
 			return models.Question{}, fmt.Errorf("must have exactly 4 answers")
@@ -90,13 +123,41 @@ func ValidateNewQuestionWithType(forceType string) (models.Question, error) {
 			correctCount++
 		}
 	}
-	if correctCount != 1 {
-		return models.Question{}, fmt.Errorf("must have exactly one correct answer")
+	switch question.Qtype {
+	case "numeric":
+	case "ordering":
+		if correctCount != 0 {
+			return models.Question{}, fmt.Errorf("ordering answers use position instead of is_correct")
+		}
+	case "multiple_choice":
+		if correctCount == 0 {
+			return models.Question{}, fmt.Errorf("must have at least one correct answer")
+		}
+	case "text_input":
+		if correctCount != len(question.Answers) {
+			return models.Question{}, fmt.Errorf("every text_input answer must be correct")
+		}
+	default:
+		if correctCount != 1 {
+			return models.Question{}, fmt.Errorf("must have exactly one correct answer")
+		}
 	}
 
 	if question.Slug == "default-question-slug" {
 		return models.Question{}, fmt.Errorf("slug cannot be the default template value 'default-question-slug' \nedit datasets/new-question.json to set a unique slug")
 	}
+	if question.Slug == "default-multiple-choice-question-slug" {
+		return models.Question{}, fmt.Errorf("slug cannot be the default template value 'default-multiple-choice-question-slug' \nedit %s to set a unique slug", utils.NewQuestionMultipleChoiceFile)
+	}
+	if question.Slug == "default-ordering-question-slug" {
+		return models.Question{}, fmt.Errorf("slug cannot be the default template value 'default-ordering-question-slug' \nedit %s to set a unique slug", utils.NewQuestionOrderingFile)
+	}
+	if question.Slug == "default-numeric-question-slug" {
+		return models.Question{}, fmt.Errorf("slug cannot be the default template value 'default-numeric-question-slug' \nedit %s to set a unique slug", utils.NewQuestionNumericFile)
+	}
+	if question.Slug == "default-text-input-question-slug" {
+		return models.Question{}, fmt.Errorf("slug cannot be the default template value 'default-text-input-question-slug' \nedit %s to set a unique slug", utils.NewQuestionTextInputFile)
+	}
 	if question.Theme.Slug == "default-theme" {
 		return models.Question{}, fmt.Errorf("theme slug cannot be the default template value 'default-theme' \nedit datasets/new-question.json to set a unique theme slug")
 	}
@@ -136,6 +197,11 @@ func ValidateNewQuestionWithType(forceType string) (models.Question, error) {
 	if err := checks.ValidateQuestionStrict(question); err != nil {
 		return models.Question{}, fmt.Errorf("validation failed:\n%v", err)
 	}
+	if question.Media != nil {
+		if err := checks.ValidateMediaFile(filepath.Join(utils.QuestionAssetsDir, question.Media.File)); err != nil {
+			return models.Question{}, fmt.Errorf("invalid media: %v \nmedia files go in %s", err, utils.QuestionAssetsDir)
+		}
+	}
 
 	return question, nil
 }
@@ -153,7 +219,11 @@ func AddValidatedQuestion(question models.Question) string {
 
 	message := fmt.Sprintf("✔ Question '%s' added successfully at line %d\n\n", question.Slug, lineNumber)
 	message += "Next steps:\n"
-	message += "  1. git add datasets/general-knowledge/questions.ndjson\n"
+	if question.Media != nil {
+		message += "  1. git add datasets/general-knowledge/questions.ndjson " + filepath.ToSlash(filepath.Join(utils.QuestionAssetsDir, question.Media.File)) + "\n"
+	} else {
+		message += "  1. git add datasets/general-knowledge/questions.ndjson\n"
+	}
 	message += "  2. git commit -m \"feat: add " + question.Slug + "\"\n"
 	message += "  3. git push \n"
 	message += "  4. Create a Pull Request in Github\n\n"
@@ -200,7 +270,7 @@ func SyncThemes() string {
 	return fmt.Sprintf("✔ Themes synced successfully\n  - %d questions\n  - %d themes\n  - %d subthemes\n  - %d tags", len(questions), len(themeSlugs), len(subthemeSlugs), len(tagSlugs))
 }
 
-func BumpVersion() (string, error) {
+func BumpVersion(key ed25519.PrivateKey) (string, error) {
 	data, err := os.ReadFile(utils.ManifestFile)
 	if err != nil {
 		return "", fmt.Errorf("error reading manifest: %v", err)
@@ -244,25 +314,43 @@ func BumpVersion() (string, error) {
 	manifest.Version = newVersion
 	manifest.UpdatedAt = time.Now()
 
-	checksums, err := calculateChecksums()
+	media, err := referencedMedia()
+	if err != nil {
+		return "", fmt.Errorf("error listing media: %v", err)
+	}
+	checksums, err := calculateChecksums(media)
 	if err != nil {
 		return "", fmt.Errorf("error calculating checksums: %v", err)
 	}
 	manifest.Checksums = checksums
+	manifest.Counts["media"] = len(media)
 
 	updatedData, err := json.MarshalIndent(manifest, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("error marshaling manifest: %v", err)
 	}
 
-	if err := os.WriteFile(utils.ManifestFile, updatedData, 0644); err != nil {
+	if err := writeManifest(utils.ManifestFile, updatedData, key); err != nil {
 		return "", fmt.Errorf("error writing manifest: %v", err)
 	}
 
-	return fmt.Sprintf("✔ Version bumped: %s → %s\n✔ Checksums calculated and updated", strings.Join(parts, "."), newVersion), nil
+	message := fmt.Sprintf("✔ Version bumped: %s → %s\n✔ Checksums calculated and updated", strings.Join(parts, "."), newVersion)
+	if key != nil {
+		message += "\n✔ Manifest signed"
+	}
+	return message, nil
 }
 
-func BumpGeographyVersion() (string, error) {
+func BumpGeographyVersion(fix bool, key ed25519.PrivateKey) (string, error) {
+	var fixed, skipped []string
+	if fix {
+		var err error
+		fixed, skipped, err = FixContinentAggregates()
+		if err != nil {
+			return "", fmt.Errorf("error fixing continent totals: %v", err)
+		}
+	}
+
 	data, err := os.ReadFile(utils.GeographyManifestFile)
 	if err != nil {
 		return "", fmt.Errorf("error reading geography manifest: %v", err)
@@ -312,11 +400,9 @@ func BumpGeographyVersion() (string, error) {
 	manifest.Counts["regions"] = len(regions)
 
 	flagCount := 0
-	if entries, err := os.ReadDir(utils.FlagsSVGDir); err == nil {
-		for _, entry := range entries {
-			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".svg") {
-				flagCount++
-			}
+	for fileName := range checksums {
+		if strings.HasSuffix(fileName, ".svg") {
+			flagCount++
 		}
 	}
 	manifest.Counts["flags"] = flagCount
@@ -326,12 +412,80 @@ func BumpGeographyVersion() (string, error) {
 		return "", fmt.Errorf("error marshaling manifest: %v", err)
 	}
 
-	if err := os.WriteFile(utils.GeographyManifestFile, updatedData, 0644); err != nil {
+	if err := writeManifest(utils.GeographyManifestFile, updatedData, key); err != nil {
 		return "", fmt.Errorf("error writing manifest: %v", err)
 	}
 
-	return fmt.Sprintf("✔ Geography version bumped: %s → %s\n✔ Checksums calculated and updated\n✔ Counts updated: %d countries, %d continents, %d regions, %d flags",
-		strings.Join(parts, "."), newVersion, len(countries), len(continents), len(regions), flagCount), nil
+	message := fmt.Sprintf("✔ Geography version bumped: %s → %s\n✔ Checksums calculated and updated\n✔ Counts updated: %d countries, %d continents, %d regions, %d flags",
+		strings.Join(parts, "."), newVersion, len(countries), len(continents), len(regions), flagCount)
+	if len(fixed) > 0 {
+		message += fmt.Sprintf("\n✔ Continent totals recomputed: %s", strings.Join(fixed, ", "))
+	}
+	if len(skipped) > 0 {
+		message += fmt.Sprintf("\n⚠ Continent totals left as they are, they list transcontinental countries: %s", strings.Join(skipped, ", "))
+	}
+	if key != nil {
+		message += "\n✔ Manifest signed"
+	}
+	return message, nil
+}
+
+// FixContinentAggregates sets the population and area of each continent to
+// the sum of its countries. Continents that list a transcontinental country
+// would get its whole population and area, so they are skipped and returned
+// apart, to be fixed by hand.
+func FixContinentAggregates() (fixed, skipped []string, err error) {
+	countries, err := utils.LoadCountries()
+	if err != nil {
+		return nil, nil, err
+	}
+	continents, err := utils.LoadContinents()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, c := range continents {
+		population, area, transcontinental := checks.ContinentTotals(c, countries)
+		switch {
+		case len(transcontinental) > 0:
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", c.Slug, strings.Join(transcontinental, ", ")))
+		case c.Population != population || c.AreaKm2 != area:
+			continents[i].Population = population
+			continents[i].AreaKm2 = area
+			fixed = append(fixed, c.Slug)
+		}
+	}
+	if len(fixed) == 0 {
+		return nil, skipped, nil
+	}
+
+	// Only the lines of the fixed continents are rewritten, the others keep
+	// their formatting.
+	data, err := os.ReadFile(utils.ContinentsFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	i := 0
+	for j, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if utils.Contains(fixed, continents[i].Slug) {
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(continents[i]); err != nil {
+				return nil, nil, err
+			}
+			lines[j] = strings.TrimSuffix(buf.String(), "\n")
+		}
+		i++
+	}
+	if err := os.WriteFile(utils.ContinentsFile, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return nil, nil, err
+	}
+	return fixed, skipped, nil
 }
 
 func InitCultpediaDataset(targetDir, datasetName string) (string, error) {
@@ -391,6 +545,22 @@ func writeSlugFile(filePath string, slugs map[string]bool) error {
 	return os.WriteFile(filePath, []byte(data), 0644)
 }
 
+// writeManifest signs the manifest when a key is given. Without a key, any
+// previous signature is removed since it no longer matches.
+func writeManifest(path string, data []byte, key ed25519.PrivateKey) error {
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	signaturePath := path + manifest.SignatureSuffix
+	if key == nil {
+		if err := os.Remove(signaturePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(signaturePath, manifest.Sign(data, key), 0644)
+}
+
 func updateManifest(questionCount, themeCount, subthemeCount, tagCount int) error {
 	data, err := os.ReadFile(utils.ManifestFile)
 	if err != nil {
@@ -412,10 +582,37 @@ func updateManifest(questionCount, themeCount, subthemeCount, tagCount int) erro
 		return err
 	}
 
-	return os.WriteFile(utils.ManifestFile, updatedData, 0644)
+	return writeManifest(utils.ManifestFile, updatedData, nil)
 }
 
-func calculateChecksums() (map[string]string, error) {
+// referencedMedia returns the sorted names of the media files referenced by
+// the questions. Files of the assets directory that no question references
+// are left out of the manifest, so they are neither hashed nor counted.
+func referencedMedia() ([]string, error) {
+	questions, err := utils.LoadQuestions()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var media []string
+	for _, q := range questions {
+		if q.Media == nil || seen[q.Media.File] {
+			continue
+		}
+		if _, ok := checks.MediaContentType(q.Media.File); !ok {
+			return nil, fmt.Errorf("question %s: invalid media file name '%s'", q.Slug, q.Media.File)
+		}
+		seen[q.Media.File] = true
+		media = append(media, q.Media.File)
+	}
+	sort.Strings(media)
+	return media, nil
+}
+
+func calculateChecksums(media []string) (map[string]string, error) {
 	checksums := make(map[string]string)
 	files := []string{
 		utils.QuestionsFile,
@@ -437,6 +634,15 @@ func calculateChecksums() (map[string]string, error) {
 		checksums[fileName] = hash
 	}
 
+	for _, name := range media {
+		filePath := filepath.Join(utils.QuestionAssetsDir, name)
+		hash, err := calculateSHA256(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error calculating hash for %s: %v", filePath, err)
+		}
+		checksums[strings.TrimPrefix(filePath, "datasets/general-knowledge/")] = hash
+	}
+
 	return checksums, nil
 }
 
@@ -461,6 +667,22 @@ func calculateGeographyChecksums() (map[string]string, error) {
 		checksums[fileName] = hash
 	}
 
+	entries, err := os.ReadDir(utils.FlagsSVGDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading flags: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".svg") {
+			continue
+		}
+		filePath := filepath.Join(utils.FlagsSVGDir, entry.Name())
+		hash, err := calculateSHA256(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error calculating hash for %s: %v", filePath, err)
+		}
+		checksums[strings.TrimPrefix(filePath, "datasets/geography/")] = hash
+	}
+
 	return checksums, nil
 }
 
@@ -523,72 +745,11 @@ func calculateEmptySHA256() string {
 }
 
 func ResetTemplate(questionType string) error {
-	if questionType == "true_false" {
-		return resetTrueFalseTemplate()
+	template, ok := utils.QuestionTemplates[questionType]
+	if !ok {
+		template = utils.QuestionTemplates["single_choice"]
 	}
-	return resetSingleChoiceTemplate()
-}
-
-func resetSingleChoiceTemplate() error {
-	template := `{
-  "kind": "question",
-  "version": "1.0",
-  "slug": "default-question-slug",
-  "theme": { "slug": "default-theme" },
-  "subthemes": [ { "slug": "default-subtheme1" }, { "slug": "default-subtheme2" } ],
-  "tags": [ { "slug": "default-tag1" }, { "slug": "default-tag2" } ],
-  "qtype": "single_choice",
-  "difficulty": "beginner",
-  "estimated_seconds": 15,
-  "points": 1.0,
-  "shuffle_answers": true,
-  "i18n": {
-    "fr": { "title": "Titre par défaut", "stem": "Question par défaut ?", "explanation": "Explication par défaut." },
-    "en": { "title": "Default Title", "stem": "Default question?", "explanation": "Default explanation." },
-    "es": { "title": "Título por defecto", "stem": "¿Pregunta por defecto?", "explanation": "Explicación por defecto." }
-  },
-  "answers": [
-    { "slug": "default-answer1", "is_correct": true,  "i18n": { "fr": { "label": "Réponse 1" }, "en": { "label": "Answer 1" }, "es": { "label": "Respuesta 1" } } },
-    { "slug": "default-answer2", "is_correct": false, "i18n": { "fr": { "label": "Réponse 2" }, "en": { "label": "Answer 2" }, "es": { "label": "Respuesta 2" } } },
-    { "slug": "default-answer3", "is_correct": false, "i18n": { "fr": { "label": "Réponse 3" }, "en": { "label": "Answer 3" }, "es": { "label": "Respuesta 3" } } },
-    { "slug": "default-answer4", "is_correct": false, "i18n": { "fr": { "label": "Réponse 4" }, "en": { "label": "Answer 4" }, "es": { "label": "Respuesta 4" } } }
-  ],
-  "sources": [
-    "https://example.com/default-source"
-  ]
-}
-`
-	return os.WriteFile(utils.NewQuestionFile, []byte(template), 0644)
-}
-
-func resetTrueFalseTemplate() error {
-	template := `{
-  "kind": "question",
-  "version": "1.0",
-  "slug": "default-true-false-question-slug",
-  "theme": { "slug": "default-theme" },
-  "subthemes": [ { "slug": "default-subtheme1" }, { "slug": "default-subtheme2" } ],
-  "tags": [ { "slug": "default-tag1" }, { "slug": "default-tag2" } ],
-  "qtype": "true_false",
-  "difficulty": "beginner",
-  "estimated_seconds": 10,
-  "points": 1.0,
-  "shuffle_answers": false,
-  "i18n": {
-    "fr": { "title": "Titre vrai/faux", "stem": "Cette affirmation est-elle vraie ou fausse?", "explanation": "Explication par défaut à remplacer par votre explication détaillée." },
-    "en": { "title": "True/False Title", "stem": "Is this statement true or false?", "explanation": "Default explanation to replace with your detailed explanation." },
-    "es": { "title": "Titulo verdadero/falso", "stem": "Es verdadera o falsa esta afirmacion?", "explanation": "Explicación por defecto a reemplazar con su explicación detallada." }
-  },
-  "answers": [
-    { "slug": "true", "is_correct": true, "i18n": { "fr": { "label": "Vrai" }, "en": { "label": "True" }, "es": { "label": "Verdadero" } } },
-    { "slug": "false", "is_correct": false, "i18n": { "fr": { "label": "Faux" }, "en": { "label": "False" }, "es": { "label": "Falso" } } }
-  ],
-  "sources": [
-    "https://example.com/default-source"
-  ]
-}
-`
-	return os.WriteFile(utils.NewQuestionTrueFalseFile, []byte(template), 0644)
+	return os.WriteFile(template.File, []byte(template.Content), 0644)
 }
 
 func GetAvailableThemes() ([]string, error) {
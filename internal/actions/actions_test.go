@@ -0,0 +1,153 @@
+package actions
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+	"cultpedia/pkg/manifest"
+)
+
+func TestCalculateGeographyChecksums(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	writeNDJSON(t, utils.CountriesFile, models.Country{Slug: "fr", Flag: "fr"})
+	if err := os.MkdirAll(utils.FlagsSVGDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(utils.FlagsSVGDir, "fr.svg"), []byte("<svg/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(utils.FlagsSVGDir, "README.md"), []byte("flags"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checksums, err := calculateGeographyChecksums()
+	if err != nil {
+		t.Fatalf("calculateGeographyChecksums() returned unexpected error: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte("<svg/>"))
+	expected := map[string]string{
+		"countries.ndjson":        checksums["countries.ndjson"],
+		"continents.ndjson":       calculateEmptySHA256(),
+		"regions.ndjson":          calculateEmptySHA256(),
+		"assets/flags/svg/fr.svg": "sha256-" + hex.EncodeToString(sum[:]),
+	}
+	if !reflect.DeepEqual(checksums, expected) {
+		t.Errorf("checksums = %v, expected %v", checksums, expected)
+	}
+}
+
+func TestFixContinentAggregates(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	writeNDJSON(t, utils.CountriesFile,
+		models.Country{Slug: "fr", Population: 68000000, AreaKm2: 551695},
+		models.Country{Slug: "be", Population: 11800000, AreaKm2: 30528.5},
+		models.Country{Slug: "jp", Population: 124000000, AreaKm2: 377930},
+		models.Country{Slug: "ru", Population: 146000000, AreaKm2: 17098246},
+	)
+	writeNDJSON(t, utils.ContinentsFile,
+		models.Continent{Slug: "europe", Name: map[string]string{"en": "Europe"}, Countries: []string{"fr", "be"}, Population: 1, AreaKm2: 1},
+		models.Continent{Slug: "asia", Name: map[string]string{"en": "Asia"}, Countries: []string{"jp", "ru"}, Population: 1, AreaKm2: 1},
+	)
+
+	fixed, skipped, err := FixContinentAggregates()
+	if err != nil {
+		t.Fatalf("FixContinentAggregates() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(fixed, []string{"europe"}) || !reflect.DeepEqual(skipped, []string{"asia (ru)"}) {
+		t.Errorf("fixed = %v, skipped = %v, expected [europe] and [asia (ru)]", fixed, skipped)
+	}
+
+	continents, err := utils.LoadContinents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if continents[0].Population != 79800000 || continents[0].AreaKm2 != 582223.5 || continents[0].Name["en"] != "Europe" {
+		t.Errorf("unexpected continent after fix: %+v", continents[0])
+	}
+	if continents[1].Population != 1 || continents[1].AreaKm2 != 1 {
+		t.Errorf("a continent listing a transcontinental country should be left as it is: %+v", continents[1])
+	}
+}
+
+func TestCalculateChecksums(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	writeNDJSON(t, utils.QuestionsFile,
+		models.Question{Slug: "q1", Media: &models.Media{File: "mona-lisa.jpg"}},
+		models.Question{Slug: "q2", Media: &models.Media{File: "mona-lisa.jpg"}},
+		models.Question{Slug: "q3"},
+	)
+	if err := os.MkdirAll(utils.QuestionAssetsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(utils.QuestionAssetsDir, "mona-lisa.jpg"), []byte("jpeg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(utils.QuestionAssetsDir, "unused.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	media, err := referencedMedia()
+	if err != nil {
+		t.Fatalf("referencedMedia() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(media, []string{"mona-lisa.jpg"}) {
+		t.Errorf("referencedMedia() = %v, expected [mona-lisa.jpg]", media)
+	}
+
+	checksums, err := calculateChecksums(media)
+	if err != nil {
+		t.Fatalf("calculateChecksums() returned unexpected error: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte("jpeg"))
+	expected := map[string]string{
+		"questions.ndjson":     checksums["questions.ndjson"],
+		"themes.ndjson":        calculateEmptySHA256(),
+		"subthemes.ndjson":     calculateEmptySHA256(),
+		"tags.ndjson":          calculateEmptySHA256(),
+		"assets/mona-lisa.jpg": "sha256-" + hex.EncodeToString(sum[:]),
+	}
+	if !reflect.DeepEqual(checksums, expected) {
+		t.Errorf("checksums = %v, expected %v", checksums, expected)
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	_, privateKey, _ := manifest.GenerateKey()
+	key, err := manifest.ParsePrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(`{"version":"1.0.1"}`)
+	if err := writeManifest("manifest.json", data, key); err != nil {
+		t.Fatalf("writeManifest() returned unexpected error: %v", err)
+	}
+	signature, err := os.ReadFile("manifest.json" + manifest.SignatureSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manifest.CheckSignature(data, signature, key.Public().(ed25519.PublicKey)); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+
+	if err := writeManifest("manifest.json", []byte(`{"version":"1.0.2"}`), nil); err != nil {
+		t.Fatalf("writeManifest() returned unexpected error: %v", err)
+	}
+	if _, err := os.Stat("manifest.json" + manifest.SignatureSuffix); !os.IsNotExist(err) {
+		t.Error("unsigned write should remove the stale signature")
+	}
+}
@@ -1,21 +1,41 @@
 package actions
 
 import (
+	"bytes"
+	"cultpedia/internal/checks"
 	"cultpedia/internal/models"
 	"cultpedia/internal/utils"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
-var apiData models.APIData
+var apiData atomic.Pointer[models.APIData]
 
-const defaultPort = "8080"
+const (
+	defaultPort = "8080"
+
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+
+	defaultQuizCount = 10
+	maxQuizCount     = 50
+	maxQuizSeed      = 1 << 53
+
+	maxRequestBodyBytes = 1 << 20
+
+	viewFull   = "full"
+	viewPlayer = "player"
+)
 
 func RunAPIServer(serverPort ...string) {
 	port := defaultPort
@@ -23,22 +43,15 @@ func RunAPIServer(serverPort ...string) {
 		port = serverPort[0]
 	}
 
-	if err := loadData(); err != nil {
-		log.Fatalf("Error loading data: %v", err)
-	}
+	loadInitialData()
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/api/questions", handleQuestions)
-	mux.HandleFunc("/api/geography/countries", handleCountries)
-	mux.HandleFunc("/api/geography/regions", handleRegions)
-	mux.HandleFunc("/api/geography/continents", handleContinents)
-	mux.HandleFunc("/api/geography/flags/", handleFlags)
-	mux.HandleFunc("/api/", handleRoot)
-	mux.HandleFunc("/", handleRoot)
+	if interval := reloadInterval(); interval > 0 {
+		go watchDatasets(interval)
+	}
 
 	server := &http.Server{
 		Addr:         ":" + port,
-		Handler:      mux,
+		Handler:      newAPIMux(),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -51,40 +64,86 @@ func RunAPIServer(serverPort ...string) {
 	}
 }
 
-func loadData() error {
+// newAPIMux serves the routes of apiRoutes and nothing else, so that the
+// OpenAPI spec built from the same table describes every route.
+func newAPIMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	registerRoutes(mux)
+	return mux
+}
+
+func loadData() (*models.APIData, error) {
+	data := &models.APIData{}
 	var err error
 
-	if err := loadManifests(); err != nil {
-		return fmt.Errorf("error loading manifests: %w", err)
+	data.Manifests, err = loadManifests()
+	if err != nil {
+		return nil, fmt.Errorf("error loading manifests: %w", err)
 	}
 
-	apiData.Questions, err = utils.LoadQuestions()
+	data.Questions, err = utils.LoadQuestions()
 	if err != nil {
-		return fmt.Errorf("error loading questions: %w", err)
+		return nil, fmt.Errorf("error loading questions: %w", err)
 	}
 
-	apiData.Countries, err = utils.LoadCountries()
+	data.Countries, err = utils.LoadCountries()
 	if err != nil {
-		return fmt.Errorf("error loading countries: %w", err)
+		return nil, fmt.Errorf("error loading countries: %w", err)
 	}
 
-	apiData.Regions, err = utils.LoadRegions()
+	data.Regions, err = utils.LoadRegions()
 	if err != nil {
-		return fmt.Errorf("error loading regions: %w", err)
+		return nil, fmt.Errorf("error loading regions: %w", err)
 	}
 
-	apiData.Continents, err = utils.LoadContinents()
+	data.Continents, err = utils.LoadContinents()
 	if err != nil {
-		return fmt.Errorf("error loading continents: %w", err)
+		return nil, fmt.Errorf("error loading continents: %w", err)
 	}
 
-	return nil
+	buildIndexes(data)
+
+	if err := computeValidators(&data.Manifests); err != nil {
+		return nil, fmt.Errorf("error computing cache validators: %w", err)
+	}
+
+	return data, nil
 }
 
-func loadManifests() error {
+func buildIndexes(data *models.APIData) {
+	data.QuestionIndex = make(map[string]int, len(data.Questions))
+	for i, q := range data.Questions {
+		data.QuestionIndex[q.Slug] = i
+	}
+
+	data.CountryIndex = make(map[string]int, len(data.Countries)*4)
+	for i, c := range data.Countries {
+		for _, key := range []string{c.Slug, c.ISOAlpha2, c.ISOAlpha3, c.ISONumerics} {
+			if key != "" {
+				data.CountryIndex[strings.ToLower(key)] = i
+			}
+		}
+	}
+
+	data.RegionIndex = make(map[string]int, len(data.Regions))
+	for i, r := range data.Regions {
+		data.RegionIndex[r.Slug] = i
+	}
+
+	data.ContinentIndex = make(map[string]int, len(data.Continents))
+	for i, c := range data.Continents {
+		data.ContinentIndex[c.Slug] = i
+	}
+
+	data.Search = buildSearchIndex(data)
+}
+
+func loadManifests() (models.Manifests, error) {
+	var manifests models.Manifests
+
 	geoFile, err := os.Open(utils.GeographyManifestFile)
 	if err != nil {
-		return err
+		return manifests, err
 	}
 	defer func() { _ = geoFile.Close() }()
 
@@ -93,16 +152,16 @@ func loadManifests() error {
 		UpdatedAt string `json:"updated_at"`
 	}
 	if err := json.NewDecoder(geoFile).Decode(&geoManifest); err != nil {
-		return err
+		return manifests, err
 	}
-	apiData.Manifests.Geography = models.ManifestInfo{
+	manifests.Geography = models.ManifestInfo{
 		Version:   geoManifest.Version,
 		UpdatedAt: geoManifest.UpdatedAt,
 	}
 
 	qFile, err := os.Open(utils.ManifestFile)
 	if err != nil {
-		return err
+		return manifests, err
 	}
 	defer func() { _ = qFile.Close() }()
 
@@ -111,17 +170,19 @@ func loadManifests() error {
 		UpdatedAt string `json:"updated_at"`
 	}
 	if err := json.NewDecoder(qFile).Decode(&qManifest); err != nil {
-		return err
+		return manifests, err
 	}
-	apiData.Manifests.GeneralKnowledge = models.ManifestInfo{
+	manifests.GeneralKnowledge = models.ManifestInfo{
 		Version:   qManifest.Version,
 		UpdatedAt: qManifest.UpdatedAt,
 	}
 
-	return nil
+	return manifests, nil
 }
 
 func handleRoot(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
@@ -131,46 +192,20 @@ func handleRoot(w http.ResponseWriter, r *http.Request) {
 		Description: "API for Cultpedia questions and geography data",
 		Datasets: map[string]interface{}{
 			"general_knowledge": map[string]string{
-				"version":    apiData.Manifests.GeneralKnowledge.Version,
-				"updated_at": apiData.Manifests.GeneralKnowledge.UpdatedAt,
+				"version":    data.Manifests.GeneralKnowledge.Version,
+				"updated_at": data.Manifests.GeneralKnowledge.UpdatedAt,
 			},
 			"geography": map[string]string{
-				"version":    apiData.Manifests.Geography.Version,
-				"updated_at": apiData.Manifests.Geography.UpdatedAt,
-			},
-		},
-		Endpoints: []models.EndpointInfo{
-			{
-				Path:        "/api/questions",
-				Method:      "GET",
-				Description: "Get all questions",
-			},
-			{
-				Path:        "/api/geography/countries",
-				Method:      "GET",
-				Description: "Get all countries",
-			},
-			{
-				Path:        "/api/geography/regions",
-				Method:      "GET",
-				Description: "Get all regions",
-			},
-			{
-				Path:        "/api/geography/continents",
-				Method:      "GET",
-				Description: "Get all continents",
-			},
-			{
-				Path:        "/api/geography/flags/{code}",
-				Method:      "GET",
-				Description: "Get country flag SVG (use ISO Alpha2 code)",
+				"version":    data.Manifests.Geography.Version,
+				"updated_at": data.Manifests.Geography.UpdatedAt,
 			},
 		},
+		Endpoints: apiEndpoints(),
 		Stats: map[string]int{
-			"questions":  len(apiData.Questions),
-			"countries":  len(apiData.Countries),
-			"regions":    len(apiData.Regions),
-			"continents": len(apiData.Continents),
+			"questions":  len(data.Questions),
+			"countries":  len(data.Countries),
+			"regions":    len(data.Regions),
+			"continents": len(data.Continents),
 		},
 	}
 
@@ -178,44 +213,371 @@ func handleRoot(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleQuestions(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+	data := apiData.Load()
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	p, err := parsePresentation(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filtered := filterQuestions(data.Questions, parseQuestionFilter(r))
+	total := len(filtered)
+
+	start := min(offset, total)
+	end := min(start+limit, total)
+	page := filtered[start:end]
+
+	questions, used := presentQuestions(page, p)
+	response := models.PaginatedResponse{
+		Data:   questions,
+		Count:  len(page),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	if end < total {
+		next := end
+		response.NextOffset = &next
+	}
+
+	setContentLanguage(w, r, p.lang, used)
+	writeJSON(w, http.StatusOK, response)
+}
+
+func handleQuestion(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
+	p, err := parsePresentation(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slug := r.PathValue("slug")
+	i, ok := data.QuestionIndex[slug]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("question '%s' not found", slug))
+		return
+	}
+
+	item, used := presentQuestion(data.Questions[i], p)
+	setContentLanguage(w, r, p.lang, used)
+	writeJSON(w, http.StatusOK, models.ItemResponse{Data: item})
+}
+
+func handleAnswer(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
+	slug := r.PathValue("slug")
+	q, ok := findQuestion(data, slug)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("question '%s' not found", slug))
+		return
+	}
+
+	var submission models.AnswerSubmission
+	if err := decodeJSONBody(w, r, &submission); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if submission.Lang == "" {
+		lang, err := preferredLanguage(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if query := strings.ToLower(r.URL.Query().Get("lang")); query == "" || query == negotiateLanguage {
+			w.Header().Add("Vary", "Accept-Language")
+		}
+		submission.Lang = resolveLanguage(q.I18n, lang)
+	}
+
+	result, err := checkAnswer(q, submission)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Language", result.Lang)
+	writeJSON(w, http.StatusOK, result)
+}
+
+func handlePreflight(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"data":  apiData.Questions,
-		"count": len(apiData.Questions),
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept-Language")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleQuiz(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
+	count, seed, seeded, err := parseQuizParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p, err := parsePresentation(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if seeded {
+		w = conditional(w, r, requestValidators(r, data, scopeGeneralKnowledge))
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+
+	quiz := buildQuiz(filterQuestions(data.Questions, parseQuestionFilter(r)), count, seed)
+	questions, used := presentQuestions(quiz, p)
+	setContentLanguage(w, r, p.lang, used)
+	writeJSON(w, http.StatusOK, models.QuizResponse{
+		Seed:  seed,
+		Count: len(quiz),
+		Data:  questions,
+	})
+}
+
+func handleGeographyQuiz(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
+	count, seed, seeded, err := parseQuizParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p, err := parsePresentation(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	kinds, err := ParseGeoQuestionKinds(r.URL.Query().Get("kind"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if seeded {
+		w = conditional(w, r, requestValidators(r, data, scopeGeography))
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+
+	quiz, err := generateGeographyQuestions(data.Countries, kinds, count, seed)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	questions, used := presentQuestions(quiz, p)
+	setContentLanguage(w, r, p.lang, used)
+	writeJSON(w, http.StatusOK, models.QuizResponse{
+		Seed:  seed,
+		Count: len(quiz),
+		Data:  questions,
 	})
 }
 
+func parseQuizParams(r *http.Request) (count int, seed int64, seeded bool, err error) {
+	query := r.URL.Query()
+
+	count = defaultQuizCount
+	if raw := query.Get("count"); raw != "" {
+		count, err = strconv.Atoi(raw)
+		if err != nil || count < 1 || count > maxQuizCount {
+			return 0, 0, false, fmt.Errorf("count must be an integer between 1 and %d", maxQuizCount)
+		}
+	}
+
+	raw := query.Get("seed")
+	if raw == "" {
+		return count, rand.Int63n(maxQuizSeed), false, nil
+	}
+	seed, err = strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("seed must be an integer")
+	}
+	return count, seed, true, nil
+}
+
+func buildQuiz(pool []models.Question, count int, seed int64) []models.Question {
+	rng := rand.New(rand.NewSource(seed))
+
+	quiz := make([]models.Question, len(pool))
+	copy(quiz, pool)
+	rng.Shuffle(len(quiz), func(i, j int) { quiz[i], quiz[j] = quiz[j], quiz[i] })
+	if count < len(quiz) {
+		quiz = quiz[:count]
+	}
+
+	for i := range quiz {
+		if !quiz[i].ShuffleAnswers || quiz[i].Qtype == "true_false" {
+			continue
+		}
+		answers := make([]models.Answer, len(quiz[i].Answers))
+		copy(answers, quiz[i].Answers)
+		rng.Shuffle(len(answers), func(a, b int) { answers[a], answers[b] = answers[b], answers[a] })
+		quiz[i].Answers = answers
+	}
+
+	return quiz
+}
+
 func handleCountries(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"data":  apiData.Countries,
-		"count": len(apiData.Countries),
+	data := apiData.Load()
+
+	lang, err := requestedLanguage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, used := presentCountries(data.Countries, lang)
+	setContentLanguage(w, r, lang, used)
+	writeJSON(w, http.StatusOK, models.ListResponse{
+		Data:  items,
+		Count: len(data.Countries),
 	})
 }
 
+func handleCountry(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
+	lang, err := requestedLanguage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	code := r.PathValue("code")
+	i, ok := data.CountryIndex[strings.ToLower(code)]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("country '%s' not found", code))
+		return
+	}
+
+	var item interface{} = data.Countries[i]
+	var used []string
+	if lang != "" {
+		localized := localizeCountry(data.Countries[i], lang)
+		item, used = localized, []string{localized.Lang}
+	}
+	setContentLanguage(w, r, lang, used)
+	writeJSON(w, http.StatusOK, models.ItemResponse{Data: item})
+}
+
 func handleRegions(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"data":  apiData.Regions,
-		"count": len(apiData.Regions),
+	data := apiData.Load()
+
+	lang, err := requestedLanguage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, used := presentRegions(data.Regions, lang)
+	setContentLanguage(w, r, lang, used)
+	writeJSON(w, http.StatusOK, models.ListResponse{
+		Data:  items,
+		Count: len(data.Regions),
 	})
 }
 
+func handleRegion(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
+	lang, err := requestedLanguage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slug := r.PathValue("slug")
+	i, ok := data.RegionIndex[slug]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("region '%s' not found", slug))
+		return
+	}
+
+	var item interface{} = data.Regions[i]
+	var used []string
+	if lang != "" {
+		localized := localizeRegion(data.Regions[i], lang)
+		item, used = localized, []string{localized.Lang}
+	}
+	setContentLanguage(w, r, lang, used)
+	writeJSON(w, http.StatusOK, models.ItemResponse{Data: item})
+}
+
 func handleContinents(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"data":  apiData.Continents,
-		"count": len(apiData.Continents),
+	data := apiData.Load()
+
+	lang, err := requestedLanguage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, used := presentContinents(data.Continents, lang)
+	setContentLanguage(w, r, lang, used)
+	writeJSON(w, http.StatusOK, models.ListResponse{
+		Data:  items,
+		Count: len(data.Continents),
 	})
 }
 
+func handleContinent(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
+	lang, err := requestedLanguage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slug := r.PathValue("slug")
+	i, ok := data.ContinentIndex[slug]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("continent '%s' not found", slug))
+		return
+	}
+
+	var item interface{} = data.Continents[i]
+	var used []string
+	if lang != "" {
+		localized := localizeContinent(data.Continents[i], lang)
+		item, used = localized, []string{localized.Lang}
+	}
+	setContentLanguage(w, r, lang, used)
+	writeJSON(w, http.StatusOK, models.ItemResponse{Data: item})
+}
+
+// findQuestion looks a slug up in the dataset, then among the questions of
+// geography quizzes, which are generated again from their slug.
+func findQuestion(data *models.APIData, slug string) (models.Question, bool) {
+	if i, ok := data.QuestionIndex[slug]; ok {
+		return data.Questions[i], true
+	}
+	return findGeographyQuestion(data.Countries, slug)
+}
+
+func questionFlagURL(q models.Question) string {
+	if q.Flag == "" {
+		return ""
+	}
+	return "/api/questions/" + q.Slug + "/flag"
+}
+
 func handleFlags(w http.ResponseWriter, r *http.Request) {
-	code := strings.TrimPrefix(r.URL.Path, "/api/geography/flags/")
-	code = strings.TrimSuffix(code, ".svg")
+	code := strings.TrimSuffix(r.PathValue("code"), ".svg")
 	code = strings.ToLower(code)
 
 	if code == "" {
@@ -229,17 +591,74 @@ func handleFlags(w http.ResponseWriter, r *http.Request) {
 	}
 
 	flagPath := filepath.Join(utils.FlagsSVGDir, code+".svg")
-
-	if _, err := os.Stat(flagPath); os.IsNotExist(err) {
+	info, err := os.Stat(flagPath)
+	if err != nil || info.IsDir() {
 		http.Error(w, "Flag not found", http.StatusNotFound)
 		return
 	}
 
 	w.Header().Set("Content-Type", "image/svg+xml")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", fmt.Sprintf(`W/"flag-%s-%d-%d"`, code, info.Size(), info.ModTime().Unix()))
 	http.ServeFile(w, r, flagPath)
 }
 
+// handleQuestionFlag serves the flag of a question under the question slug,
+// without the flag-icons id that names the country, so that players cannot
+// read the answer from the URL or the SVG.
+func handleQuestionFlag(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
+	slug := r.PathValue("slug")
+	q, ok := findQuestion(data, slug)
+	if !ok || q.Flag == "" {
+		http.Error(w, "Flag not found", http.StatusNotFound)
+		return
+	}
+
+	flagPath := filepath.Join(utils.FlagsSVGDir, q.Flag+".svg")
+	info, err := os.Stat(flagPath)
+	if err != nil || info.IsDir() {
+		http.Error(w, "Flag not found", http.StatusNotFound)
+		return
+	}
+	svg, err := os.ReadFile(flagPath)
+	if err != nil {
+		http.Error(w, "Flag not found", http.StatusNotFound)
+		return
+	}
+	svg = bytes.Replace(svg, []byte(` id="flag-icons-`+q.Flag+`"`), nil, 1)
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", fmt.Sprintf(`W/"flag-%s-%d-%d"`, slug, info.Size(), info.ModTime().Unix()))
+	http.ServeContent(w, r, "", info.ModTime(), bytes.NewReader(svg))
+}
+
+func handleMedia(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("file")
+	contentType, ok := checks.MediaContentType(name)
+	if !ok {
+		http.Error(w, "Invalid media file name", http.StatusBadRequest)
+		return
+	}
+
+	mediaPath := filepath.Join(utils.QuestionAssetsDir, name)
+	info, err := os.Stat(mediaPath)
+	if err != nil || info.IsDir() {
+		http.Error(w, "Media not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", fmt.Sprintf(`W/"media-%s-%d-%d"`, name, info.Size(), info.ModTime().Unix()))
+	http.ServeFile(w, r, mediaPath)
+}
+
 func isAlphaOnly(s string) bool {
 	for _, c := range s {
 		if c < 'a' || c > 'z' {
@@ -248,3 +667,229 @@ func isAlphaOnly(s string) bool {
 	}
 	return true
 }
+
+type questionFilter struct {
+	Theme      string
+	Subtheme   string
+	Tag        string
+	Difficulty string
+	Qtype      string
+	Lang       string
+}
+
+func parseQuestionFilter(r *http.Request) questionFilter {
+	query := r.URL.Query()
+	filter := questionFilter{
+		Theme:      strings.TrimSpace(query.Get("theme")),
+		Subtheme:   strings.TrimSpace(query.Get("subtheme")),
+		Tag:        strings.TrimSpace(query.Get("tag")),
+		Difficulty: strings.TrimSpace(query.Get("difficulty")),
+		Qtype:      strings.TrimSpace(query.Get("qtype")),
+		Lang:       strings.TrimSpace(query.Get("lang")),
+	}
+	if filter.Lang == allLanguages {
+		filter.Lang = ""
+	}
+	return filter
+}
+
+func (f questionFilter) matches(q models.Question) bool {
+	if f.Theme != "" && q.Theme.Slug != f.Theme {
+		return false
+	}
+	if f.Subtheme != "" && !hasThemeSlug(q.Subthemes, f.Subtheme) {
+		return false
+	}
+	if f.Tag != "" && !hasThemeSlug(q.Tags, f.Tag) {
+		return false
+	}
+	if f.Difficulty != "" && q.Difficulty != f.Difficulty {
+		return false
+	}
+	if f.Qtype != "" && q.Qtype != f.Qtype {
+		return false
+	}
+	if f.Lang != "" {
+		if _, ok := q.I18n[f.Lang]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func filterQuestions(questions []models.Question, f questionFilter) []models.Question {
+	filtered := make([]models.Question, 0, len(questions))
+	for _, q := range questions {
+		if f.matches(q) {
+			filtered = append(filtered, q)
+		}
+	}
+	return filtered
+}
+
+func hasThemeSlug(themes []models.Theme, slug string) bool {
+	for _, t := range themes {
+		if t.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	query := r.URL.Query()
+	limit = defaultPageLimit
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return 0, 0, fmt.Errorf("limit must be an integer between 1 and %d", maxPageLimit)
+		}
+	}
+
+	if raw := query.Get("offset"); raw != "" {
+		offset, err = strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+
+	return limit, offset, nil
+}
+
+func parseView(r *http.Request) (string, error) {
+	view := r.URL.Query().Get("view")
+	switch view {
+	case "", viewFull:
+		return viewFull, nil
+	case viewPlayer:
+		return viewPlayer, nil
+	default:
+		return "", fmt.Errorf("view must be '%s' or '%s'", viewFull, viewPlayer)
+	}
+}
+
+type presentation struct {
+	view string
+	lang string
+}
+
+func parsePresentation(r *http.Request) (presentation, error) {
+	view, err := parseView(r)
+	if err != nil {
+		return presentation{}, err
+	}
+	lang, err := requestedLanguage(r)
+	if err != nil {
+		return presentation{}, err
+	}
+	return presentation{view: view, lang: lang}, nil
+}
+
+// presentQuestions also returns the language of each localized question, or
+// nil when the questions keep all their translations.
+func presentQuestions(questions []models.Question, p presentation) (interface{}, []string) {
+	switch {
+	case p.lang != "":
+		localized := make([]models.LocalizedQuestion, len(questions))
+		used := make([]string, len(questions))
+		for i, q := range questions {
+			localized[i] = localizeQuestion(q, p.lang, p.view == viewPlayer)
+			used[i] = localized[i].Lang
+		}
+		return localized, used
+	case p.view == viewPlayer:
+		players := make([]models.PlayerQuestion, len(questions))
+		for i, q := range questions {
+			players[i] = toPlayerQuestion(q)
+		}
+		return players, nil
+	default:
+		return questions, nil
+	}
+}
+
+func presentQuestion(q models.Question, p presentation) (interface{}, []string) {
+	switch {
+	case p.lang != "":
+		localized := localizeQuestion(q, p.lang, p.view == viewPlayer)
+		return localized, []string{localized.Lang}
+	case p.view == viewPlayer:
+		return toPlayerQuestion(q), nil
+	default:
+		return q, nil
+	}
+}
+
+func toPlayerQuestion(q models.Question) models.PlayerQuestion {
+	player := models.PlayerQuestion{
+		Kind:             q.Kind,
+		Version:          q.Version,
+		Slug:             q.Slug,
+		Theme:            q.Theme,
+		Subthemes:        q.Subthemes,
+		Tags:             q.Tags,
+		Qtype:            q.Qtype,
+		Difficulty:       q.Difficulty,
+		EstimatedSeconds: q.EstimatedSeconds,
+		Points:           q.Points,
+		ShuffleAnswers:   q.ShuffleAnswers,
+		Scoring:          q.Scoring,
+		TypoTolerance:    q.TypoTolerance,
+		I18n:             make(map[string]models.PlayerI18n, len(q.I18n)),
+		Media:            q.Media,
+		FlagURL:          questionFlagURL(q),
+	}
+	for lang, content := range q.I18n {
+		player.I18n[lang] = models.PlayerI18n{Title: content.Title, Stem: content.Stem}
+	}
+	answers := playerAnswers(q)
+	player.Answers = make([]models.PlayerAnswer, len(answers))
+	for i, a := range answers {
+		player.Answers[i] = models.PlayerAnswer{Slug: a.Slug, I18n: a.I18n}
+	}
+	if q.Numeric != nil {
+		player.Unit = q.Numeric.Unit
+	}
+	return player
+}
+
+// playerAnswers sorts the answers of ordering questions by slug, since they
+// are usually written in the correct order, and hides the answers of
+// text_input questions, which are all correct.
+func playerAnswers(q models.Question) []models.Answer {
+	if q.Qtype == "text_input" {
+		return []models.Answer{}
+	}
+	if q.Qtype != "ordering" {
+		return q.Answers
+	}
+	answers := make([]models.Answer, len(q.Answers))
+	copy(answers, q.Answers)
+	sort.Slice(answers, func(i, j int) bool { return answers[i].Slug < answers[j].Slug })
+	return answers
+}
+
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, models.ErrorResponse{
+		Error:  message,
+		Status: status,
+	})
+}
@@ -0,0 +1,652 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+func createTestQuestion(slug, theme, difficulty, qtype string, tags ...string) models.Question {
+	q := models.Question{
+		Kind:           "question",
+		Slug:           slug,
+		Theme:          models.Theme{Slug: theme},
+		Qtype:          qtype,
+		Difficulty:     difficulty,
+		Points:         1.0,
+		ShuffleAnswers: true,
+		I18n: map[string]models.I18n{
+			"fr": {Title: "Titre", Stem: "Question ?", Explanation: "Explication."},
+			"en": {Title: "Title", Stem: "Question?", Explanation: "Explanation."},
+			"es": {Title: "Titulo", Stem: "¿Pregunta?", Explanation: "Explicacion."},
+		},
+	}
+	for _, tag := range tags {
+		q.Tags = append(q.Tags, models.Theme{Slug: tag})
+	}
+	for i := 0; i < 4; i++ {
+		label := fmt.Sprintf("%s-answer-%d", slug, i+1)
+		q.Answers = append(q.Answers, models.Answer{
+			Slug:      label,
+			IsCorrect: i == 0,
+			I18n:      map[string]models.Label{"fr": {Label: label}, "en": {Label: label}, "es": {Label: label}},
+		})
+	}
+	return q
+}
+
+func setTestQuestions(t *testing.T) {
+	t.Helper()
+	previous := apiData.Load()
+	t.Cleanup(func() { apiData.Store(previous) })
+
+	data := &models.APIData{
+		Questions: []models.Question{
+			createTestQuestion("history-q1", "history", "beginner", "single_choice", "france"),
+			createTestQuestion("history-q2", "history", "advanced", "true_false"),
+			createTestQuestion("science-q1", "science", "beginner", "single_choice", "physics"),
+			createTestQuestion("science-q2", "science", "intermediate", "single_choice", "france"),
+			createTestQuestion("art-q1", "art", "beginner", "single_choice"),
+		},
+		Countries: []models.Country{
+			{Slug: "fr", ISOAlpha2: "FR", ISOAlpha3: "FRA", ISONumerics: "250", Continent: "europe", Region: "western_europe"},
+			{Slug: "jp", ISOAlpha2: "JP", ISOAlpha3: "JPN", ISONumerics: "392", Continent: "asia", Region: "eastern_asia"},
+		},
+		Regions: []models.Region{
+			{Slug: "western_europe", Continent: "europe", Countries: []string{"fr"}},
+			{Slug: "eastern_asia", Continent: "asia", Countries: []string{"jp"}},
+		},
+		Continents: []models.Continent{
+			{Slug: "europe", Countries: []string{"fr"}},
+			{Slug: "asia", Countries: []string{"jp"}},
+		},
+	}
+	buildIndexes(data)
+	apiData.Store(data)
+}
+
+func serveTestRequest(method, target string, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	newAPIMux().ServeHTTP(rec, req)
+	return rec
+}
+
+func getPaginated(t *testing.T, handler http.HandlerFunc, target string) (int, models.PaginatedResponse, []models.Question) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	var raw struct {
+		models.PaginatedResponse
+		Data []models.Question `json:"data"`
+	}
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+			t.Fatalf("invalid JSON response: %v", err)
+		}
+	}
+	return rec.Code, raw.PaginatedResponse, raw.Data
+}
+
+func TestHandleQuestionsFilters(t *testing.T) {
+	setTestQuestions(t)
+
+	tests := []struct {
+		name     string
+		target   string
+		expected []string
+	}{
+		{"no filter", "/api/questions", []string{"history-q1", "history-q2", "science-q1", "science-q2", "art-q1"}},
+		{"theme", "/api/questions?theme=science", []string{"science-q1", "science-q2"}},
+		{"difficulty", "/api/questions?difficulty=beginner", []string{"history-q1", "science-q1", "art-q1"}},
+		{"qtype", "/api/questions?qtype=true_false", []string{"history-q2"}},
+		{"tag", "/api/questions?tag=france", []string{"history-q1", "science-q2"}},
+		{"combined", "/api/questions?tag=france&theme=history", []string{"history-q1"}},
+		{"all languages", "/api/questions?lang=all", []string{"history-q1", "history-q2", "science-q1", "science-q2", "art-q1"}},
+		{"single language", "/api/questions?lang=fr&theme=art", []string{"art-q1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, envelope, data := getPaginated(t, handleQuestions, tt.target)
+			if status != http.StatusOK {
+				t.Fatalf("status = %d, expected %d", status, http.StatusOK)
+			}
+			if envelope.Total != len(tt.expected) {
+				t.Errorf("total = %d, expected %d", envelope.Total, len(tt.expected))
+			}
+			if len(data) != len(tt.expected) {
+				t.Fatalf("got %d questions, expected %d", len(data), len(tt.expected))
+			}
+			for i, q := range data {
+				if q.Slug != tt.expected[i] {
+					t.Errorf("question %d = %s, expected %s", i, q.Slug, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHandleQuestionsPagination(t *testing.T) {
+	setTestQuestions(t)
+
+	t.Run("first page", func(t *testing.T) {
+		_, envelope, data := getPaginated(t, handleQuestions, "/api/questions?limit=2")
+		if len(data) != 2 || envelope.Count != 2 || envelope.Total != 5 {
+			t.Errorf("unexpected page: count=%d total=%d", envelope.Count, envelope.Total)
+		}
+		if envelope.NextOffset == nil || *envelope.NextOffset != 2 {
+			t.Errorf("next_offset = %v, expected 2", envelope.NextOffset)
+		}
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		_, envelope, data := getPaginated(t, handleQuestions, "/api/questions?limit=2&offset=4")
+		if len(data) != 1 || data[0].Slug != "art-q1" {
+			t.Errorf("unexpected last page: %v", data)
+		}
+		if envelope.NextOffset != nil {
+			t.Errorf("next_offset should be omitted on the last page")
+		}
+	})
+
+	t.Run("offset past the end", func(t *testing.T) {
+		_, envelope, data := getPaginated(t, handleQuestions, "/api/questions?offset=50")
+		if len(data) != 0 || envelope.Total != 5 {
+			t.Errorf("expected empty page with total 5, got %d items and total %d", len(data), envelope.Total)
+		}
+	})
+
+	for _, target := range []string{"/api/questions?limit=0", "/api/questions?limit=abc", "/api/questions?offset=-1", "/api/questions?limit=100000", "/api/questions?lang=de"} {
+		t.Run("invalid "+target, func(t *testing.T) {
+			status, _, _ := getPaginated(t, handleQuestions, target)
+			if status != http.StatusBadRequest {
+				t.Errorf("status = %d, expected %d", status, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestSingleResourceEndpoints(t *testing.T) {
+	setTestQuestions(t)
+
+	tests := []struct {
+		name     string
+		target   string
+		status   int
+		expected string
+	}{
+		{"question", "/api/questions/science-q2", http.StatusOK, "science-q2"},
+		{"unknown question", "/api/questions/unknown", http.StatusNotFound, ""},
+		{"country by slug", "/api/geography/countries/fr", http.StatusOK, "fr"},
+		{"country by alpha-2", "/api/geography/countries/JP", http.StatusOK, "jp"},
+		{"country by alpha-3", "/api/geography/countries/fra", http.StatusOK, "fr"},
+		{"country by numeric", "/api/geography/countries/392", http.StatusOK, "jp"},
+		{"unknown country", "/api/geography/countries/zz", http.StatusNotFound, ""},
+		{"region", "/api/geography/regions/eastern_asia", http.StatusOK, "eastern_asia"},
+		{"unknown region", "/api/geography/regions/atlantis", http.StatusNotFound, ""},
+		{"continent", "/api/geography/continents/europe", http.StatusOK, "europe"},
+		{"unknown continent", "/api/geography/continents/mu", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveTestRequest(http.MethodGet, tt.target, "")
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, expected %d", rec.Code, tt.status)
+			}
+
+			if tt.status != http.StatusOK {
+				var errResponse models.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &errResponse); err != nil || errResponse.Error == "" {
+					t.Errorf("expected JSON error body, got %q", rec.Body.String())
+				}
+				return
+			}
+
+			var response struct {
+				Data struct {
+					Slug string `json:"slug"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("invalid JSON response: %v", err)
+			}
+			if response.Data.Slug != tt.expected {
+				t.Errorf("slug = %s, expected %s", response.Data.Slug, tt.expected)
+			}
+		})
+	}
+}
+
+func quizSignature(quiz []models.Question) []string {
+	var signature []string
+	for _, q := range quiz {
+		entry := q.Slug + ":"
+		for _, a := range q.Answers {
+			entry += a.Slug + ","
+		}
+		signature = append(signature, entry)
+	}
+	return signature
+}
+
+func TestBuildQuizDeterministic(t *testing.T) {
+	setTestQuestions(t)
+
+	first := quizSignature(buildQuiz(apiData.Load().Questions, 3, 42))
+	second := quizSignature(buildQuiz(apiData.Load().Questions, 3, 42))
+
+	if len(first) != 3 {
+		t.Fatalf("expected 3 questions, got %d", len(first))
+	}
+	if strings.Join(first, "|") != strings.Join(second, "|") {
+		t.Errorf("same seed produced different quizzes:\n%v\n%v", first, second)
+	}
+
+	differs := false
+	for seed := int64(1); seed < 20 && !differs; seed++ {
+		other := quizSignature(buildQuiz(apiData.Load().Questions, 3, seed))
+		differs = strings.Join(first, "|") != strings.Join(other, "|")
+	}
+	if !differs {
+		t.Error("different seeds should produce different quizzes")
+	}
+}
+
+func TestBuildQuizDoesNotMutatePool(t *testing.T) {
+	setTestQuestions(t)
+	before := quizSignature(apiData.Load().Questions)
+
+	_ = buildQuiz(apiData.Load().Questions, len(apiData.Load().Questions), 7)
+
+	if strings.Join(before, "|") != strings.Join(quizSignature(apiData.Load().Questions), "|") {
+		t.Error("buildQuiz() must not reorder the source questions or their answers")
+	}
+}
+
+type testQuizResponse struct {
+	Seed  int64             `json:"seed"`
+	Count int               `json:"count"`
+	Data  []models.Question `json:"data"`
+}
+
+func decodeQuiz(t *testing.T, rec *httptest.ResponseRecorder) testQuizResponse {
+	t.Helper()
+	var response testQuizResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+	return response
+}
+
+func TestHandleQuiz(t *testing.T) {
+	setTestQuestions(t)
+
+	t.Run("fixed seed is reproducible", func(t *testing.T) {
+		first := serveTestRequest(http.MethodGet, "/api/quiz?count=3&seed=123", "")
+		second := serveTestRequest(http.MethodGet, "/api/quiz?count=3&seed=123", "")
+		if first.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected %d", first.Code, http.StatusOK)
+		}
+		if first.Body.String() != second.Body.String() {
+			t.Error("same seed should return the same quiz")
+		}
+
+		response := decodeQuiz(t, first)
+		if response.Seed != 123 || response.Count != 3 {
+			t.Errorf("seed = %d, count = %d, expected 123 and 3", response.Seed, response.Count)
+		}
+	})
+
+	t.Run("constraints", func(t *testing.T) {
+		rec := serveTestRequest(http.MethodGet, "/api/quiz?count=10&theme=science&difficulty=beginner", "")
+		response := decodeQuiz(t, rec)
+		if response.Count != 1 || response.Data[0].Slug != "science-q1" {
+			t.Errorf("expected only science-q1, got %v", quizSignature(response.Data))
+		}
+	})
+
+	t.Run("true_false answers keep their order", func(t *testing.T) {
+		rec := serveTestRequest(http.MethodGet, "/api/quiz?qtype=true_false&seed=5", "")
+		response := decodeQuiz(t, rec)
+		if response.Data[0].Answers[0].Slug != "history-q2-answer-1" {
+			t.Error("true_false answers should not be shuffled")
+		}
+	})
+
+	for _, target := range []string{"/api/quiz?count=0", "/api/quiz?count=500", "/api/quiz?seed=abc"} {
+		t.Run("invalid "+target, func(t *testing.T) {
+			rec := serveTestRequest(http.MethodGet, target, "")
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, expected %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestPlayerView(t *testing.T) {
+	setTestQuestions(t)
+
+	for _, target := range []string{
+		"/api/questions?view=player",
+		"/api/questions/history-q1?view=player",
+		"/api/quiz?view=player&seed=1",
+	} {
+		t.Run(target, func(t *testing.T) {
+			rec := serveTestRequest(http.MethodGet, target, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, expected %d", rec.Code, http.StatusOK)
+			}
+			body := rec.Body.String()
+			if strings.Contains(body, "is_correct") || strings.Contains(body, "explanation") {
+				t.Errorf("player view leaks answers: %s", body)
+			}
+			if !strings.Contains(body, `"answers"`) {
+				t.Errorf("player view should keep answer options: %s", body)
+			}
+		})
+	}
+
+	rec := serveTestRequest(http.MethodGet, "/api/questions?view=admin", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown view: status = %d, expected %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleAnswer(t *testing.T) {
+	setTestQuestions(t)
+
+	t.Run("correct answer", func(t *testing.T) {
+		rec := serveTestRequest(http.MethodPost, "/api/questions/history-q1/answer", `{"answer":"history-q1-answer-1","lang":"fr"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		var result models.AnswerResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON response: %v", err)
+		}
+		if !result.Correct || result.Points != 1.0 || result.Explanation != "Explication." {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("wrong answer", func(t *testing.T) {
+		rec := serveTestRequest(http.MethodPost, "/api/questions/history-q1/answer", `{"answer":"history-q1-answer-3"}`)
+		var result models.AnswerResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON response: %v", err)
+		}
+		if result.Correct || result.Points != 0 || result.CorrectAnswer.Slug != "history-q1-answer-1" || result.Lang != "en" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+
+	errorCases := []struct {
+		name   string
+		target string
+		body   string
+		status int
+	}{
+		{"unknown question", "/api/questions/unknown/answer", `{"answer":"a"}`, http.StatusNotFound},
+		{"unknown answer", "/api/questions/history-q1/answer", `{"answer":"nope"}`, http.StatusBadRequest},
+		{"missing answer", "/api/questions/history-q1/answer", `{}`, http.StatusBadRequest},
+		{"unknown language", "/api/questions/history-q1/answer", `{"answer":"history-q1-answer-1","lang":"de"}`, http.StatusBadRequest},
+		{"malformed body", "/api/questions/history-q1/answer", `{"answer":`, http.StatusBadRequest},
+		{"several answers to a single_choice question", "/api/questions/history-q1/answer", `{"answers":["history-q1-answer-1","history-q1-answer-2"]}`, http.StatusBadRequest},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveTestRequest(http.MethodPost, tt.target, tt.body)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, expected %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestCheckAnswerMultipleChoice(t *testing.T) {
+	q := createTestQuestion("mc", "art", "beginner", "multiple_choice")
+	q.Points = 3
+	q.Answers[1].IsCorrect = true
+	q.Answers[2].IsCorrect = true
+
+	tests := []struct {
+		name    string
+		scoring string
+		answers []string
+		correct bool
+		points  float64
+	}{
+		{"all correct", "", []string{"mc-answer-3", "mc-answer-1", "mc-answer-2"}, true, 3},
+		{"missing one", "", []string{"mc-answer-1", "mc-answer-2"}, false, 0},
+		{"partial missing one", "partial", []string{"mc-answer-1", "mc-answer-2"}, false, 2},
+		{"partial with a wrong pick", "partial", []string{"mc-answer-1", "mc-answer-2", "mc-answer-4"}, false, 1},
+		{"partial with every option", "partial", []string{"mc-answer-1", "mc-answer-2", "mc-answer-3", "mc-answer-4"}, false, 2},
+		{"partial only wrong", "partial", []string{"mc-answer-4"}, false, 0},
+		{"partial all correct", "partial", []string{"mc-answer-1", "mc-answer-2", "mc-answer-3"}, true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q.Scoring = tt.scoring
+			result, err := checkAnswer(q, models.AnswerSubmission{Answers: tt.answers})
+			if err != nil {
+				t.Fatalf("checkAnswer() returned unexpected error: %v", err)
+			}
+			if result.Correct != tt.correct || result.Points != tt.points {
+				t.Errorf("correct = %v, points = %v, expected %v and %v", result.Correct, result.Points, tt.correct, tt.points)
+			}
+			if len(result.CorrectAnswers) != 3 || result.CorrectAnswer != nil {
+				t.Errorf("unexpected correct answers: %+v", result)
+			}
+		})
+	}
+
+	for _, answers := range [][]string{nil, {"mc-answer-1", "nope"}, {"mc-answer-1", "mc-answer-1"}} {
+		if _, err := checkAnswer(q, models.AnswerSubmission{Answers: answers}); err == nil {
+			t.Errorf("checkAnswer(%v) should return an error", answers)
+		}
+	}
+}
+
+func TestCheckAnswerOrdering(t *testing.T) {
+	q := createTestQuestion("events", "history", "beginner", "ordering")
+	q.Points = 2
+	for i := range q.Answers {
+		q.Answers[i].IsCorrect = false
+		q.Answers[i].Position = i + 1
+	}
+
+	tests := []struct {
+		name    string
+		scoring string
+		answers []string
+		correct bool
+		points  float64
+	}{
+		{"right order", "", []string{"events-answer-1", "events-answer-2", "events-answer-3", "events-answer-4"}, true, 2},
+		{"one swap", "", []string{"events-answer-2", "events-answer-1", "events-answer-3", "events-answer-4"}, false, 0},
+		{"partial one swap", "partial", []string{"events-answer-2", "events-answer-1", "events-answer-3", "events-answer-4"}, false, 1.67},
+		{"partial reversed", "partial", []string{"events-answer-4", "events-answer-3", "events-answer-2", "events-answer-1"}, false, 0},
+		{"partial right order", "partial", []string{"events-answer-1", "events-answer-2", "events-answer-3", "events-answer-4"}, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q.Scoring = tt.scoring
+			result, err := checkAnswer(q, models.AnswerSubmission{Answers: tt.answers})
+			if err != nil {
+				t.Fatalf("checkAnswer() returned unexpected error: %v", err)
+			}
+			if result.Correct != tt.correct || result.Points != tt.points {
+				t.Errorf("correct = %v, points = %v, expected %v and %v", result.Correct, result.Points, tt.correct, tt.points)
+			}
+			if len(result.CorrectAnswers) != 4 || result.CorrectAnswers[0].Slug != "events-answer-1" {
+				t.Errorf("unexpected correct order: %+v", result.CorrectAnswers)
+			}
+		})
+	}
+
+	for _, answers := range [][]string{{"events-answer-1"}, {"events-answer-1", "events-answer-2", "events-answer-3", "events-answer-3"}} {
+		if _, err := checkAnswer(q, models.AnswerSubmission{Answers: answers}); err == nil {
+			t.Errorf("checkAnswer(%v) should return an error", answers)
+		}
+	}
+
+	q.Answers = []models.Answer{q.Answers[2], q.Answers[0], q.Answers[3], q.Answers[1]}
+	player := toPlayerQuestion(q)
+	localized := localizeQuestion(q, "en", true)
+	for i, slug := range []string{"events-answer-1", "events-answer-2", "events-answer-3", "events-answer-4"} {
+		if player.Answers[i].Slug != slug || localized.Answers[i].Slug != slug || localized.Answers[i].Position != 0 {
+			t.Fatalf("player view should list ordering answers by slug: %+v %+v", player.Answers, localized.Answers)
+		}
+	}
+}
+
+func TestCheckAnswerNumeric(t *testing.T) {
+	q := createTestQuestion("area", "geography", "advanced", "numeric")
+	q.Points = 2
+	q.Answers = []models.Answer{}
+	q.Numeric = &models.NumericAnswer{Value: 1000, Unit: "km²", RelativeTolerance: 0.1}
+
+	value := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name    string
+		scoring string
+		value   float64
+		correct bool
+		points  float64
+	}{
+		{"exact", "", 1000, true, 2},
+		{"within tolerance", "", 1090, true, 2},
+		{"outside tolerance", "", 1150, false, 0},
+		{"partial outside tolerance", "partial", 850, false, 1},
+		{"partial too far", "partial", 1250, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q.Scoring = tt.scoring
+			result, err := checkAnswer(q, models.AnswerSubmission{Value: value(tt.value)})
+			if err != nil {
+				t.Fatalf("checkAnswer() returned unexpected error: %v", err)
+			}
+			if result.Correct != tt.correct || result.Points != tt.points {
+				t.Errorf("correct = %v, points = %v, expected %v and %v", result.Correct, result.Points, tt.correct, tt.points)
+			}
+			if result.CorrectValue == nil || result.CorrectValue.Value != 1000 || *result.Value != tt.value {
+				t.Errorf("unexpected result: %+v", result)
+			}
+		})
+	}
+
+	player := toPlayerQuestion(q)
+	if player.Unit != "km²" || len(player.Answers) != 0 {
+		t.Errorf("unexpected player question: %+v", player)
+	}
+
+	q.Numeric = &models.NumericAnswer{Value: 1789}
+	if result, err := checkAnswer(q, models.AnswerSubmission{Value: value(1788)}); err != nil || result.Correct {
+		t.Errorf("an exact value should not accept 1788: %+v, %v", result, err)
+	}
+	for _, submission := range []models.AnswerSubmission{{}, {Answer: "1789"}, {Value: value(1789), Lang: "de"}} {
+		if _, err := checkAnswer(q, submission); err == nil {
+			t.Errorf("checkAnswer(%+v) should return an error", submission)
+		}
+	}
+	if _, err := checkAnswer(createTestQuestion("q", "art", "beginner", "single_choice"), models.AnswerSubmission{Answer: "q-answer-1", Value: value(1)}); err == nil {
+		t.Error("a single_choice question should not take a value")
+	}
+}
+
+func TestCheckAnswerTextInput(t *testing.T) {
+	q := createTestQuestion("capital-france", "geography", "beginner", "text_input")
+	q.TypoTolerance = 1
+	q.Answers = []models.Answer{{
+		Slug:      "paris",
+		IsCorrect: true,
+		I18n:      map[string]models.Label{"fr": {Label: "Paris"}, "en": {Label: "Paris"}, "es": {Label: "París"}},
+		Accepted:  map[string][]string{"fr": {"Ville de Paris"}, "en": {"The City of Light"}, "es": {"Ciudad de París"}},
+	}}
+
+	tests := []struct {
+		answer  string
+		lang    string
+		correct bool
+	}{
+		{"Paris", "", true},
+		{"  PARIS ", "", true},
+		{"paris", "es", true},
+		{"the city of light", "en", true},
+		{"la ville de Paris", "fr", true},
+		{"Parsi", "", false},
+		{"Pariss", "", true},
+		{"Lyon", "fr", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.answer+"/"+tt.lang, func(t *testing.T) {
+			result, err := checkAnswer(q, models.AnswerSubmission{Answer: tt.answer, Lang: tt.lang})
+			if err != nil {
+				t.Fatalf("checkAnswer() returned unexpected error: %v", err)
+			}
+			if result.Correct != tt.correct || result.CorrectAnswer == nil || result.CorrectAnswer.Slug != "paris" {
+				t.Errorf("unexpected result: %+v", result)
+			}
+		})
+	}
+
+	q.TypoTolerance = 0
+	if result, err := checkAnswer(q, models.AnswerSubmission{Answer: "Pariss"}); err != nil || result.Correct {
+		t.Errorf("a typo should not match without typo_tolerance: %+v, %v", result, err)
+	}
+	for _, submission := range []models.AnswerSubmission{{}, {Answer: " "}, {Answer: "Paris", Answers: []string{"Paris"}}, {Answer: "Paris", Lang: "de"}} {
+		if _, err := checkAnswer(q, submission); err == nil {
+			t.Errorf("checkAnswer(%+v) should return an error", submission)
+		}
+	}
+
+	if player := toPlayerQuestion(q); len(player.Answers) != 0 {
+		t.Errorf("the player view should hide text_input answers: %+v", player)
+	}
+}
+
+func TestHandleMedia(t *testing.T) {
+	setTestQuestions(t)
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(utils.QuestionAssetsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(utils.QuestionAssetsDir, "mona-lisa.jpg"), []byte("\xff\xd8\xff"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target      string
+		status      int
+		contentType string
+	}{
+		{"/api/media/mona-lisa.jpg", http.StatusOK, "image/jpeg"},
+		{"/api/media/anthem.mp3", http.StatusNotFound, ""},
+		{"/api/media/Mona-Lisa.JPG", http.StatusBadRequest, ""},
+		{"/api/media/notes.txt", http.StatusBadRequest, ""},
+		{"/api/media/..%2Fquestions.ndjson", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := serveTestRequest(http.MethodGet, tt.target, "")
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, expected %d", rec.Code, tt.status)
+			}
+			if tt.contentType != "" && rec.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %q, expected %q", rec.Header().Get("Content-Type"), tt.contentType)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package actions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+const (
+	scopeGeneralKnowledge = "general-knowledge"
+	scopeGeography        = "geography"
+	scopeAll              = "all"
+
+	cacheControl = "public, no-cache"
+)
+
+type validators struct {
+	etag         string
+	lastModified time.Time
+}
+
+var datasetFiles = map[string][]string{
+	scopeGeneralKnowledge: {
+		utils.ManifestFile,
+		utils.QuestionsFile,
+	},
+	scopeGeography: {
+		utils.GeographyManifestFile,
+		utils.CountriesFile,
+		utils.ContinentsFile,
+		utils.RegionsFile,
+	},
+}
+
+func computeValidators(manifests *models.Manifests) error {
+	var err error
+
+	manifests.GeneralKnowledge.ETag, manifests.GeneralKnowledge.LastModified, err = datasetValidators("gk", manifests.GeneralKnowledge, datasetFiles[scopeGeneralKnowledge])
+	if err != nil {
+		return err
+	}
+
+	manifests.Geography.ETag, manifests.Geography.LastModified, err = datasetValidators("geo", manifests.Geography, datasetFiles[scopeGeography])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func datasetValidators(prefix string, manifest models.ManifestInfo, files []string) (string, time.Time, error) {
+	hash := sha256.New()
+	var lastModified time.Time
+
+	if updatedAt, err := time.Parse(time.RFC3339Nano, manifest.UpdatedAt); err == nil {
+		lastModified = updatedAt
+	}
+
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		_, err = io.Copy(hash, file)
+		info, statErr := file.Stat()
+		_ = file.Close()
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("error hashing %s: %v", path, err)
+		}
+		if statErr == nil && info.ModTime().After(lastModified) {
+			lastModified = info.ModTime()
+		}
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	etag := fmt.Sprintf(`"%s-%s-%s"`, prefix, manifest.Version, checksum[:16])
+	return etag, lastModified.UTC().Truncate(time.Second), nil
+}
+
+func cacheValidators(data *models.APIData, scope string) validators {
+	gk := data.Manifests.GeneralKnowledge
+	geo := data.Manifests.Geography
+
+	switch scope {
+	case scopeGeneralKnowledge:
+		return validators{etag: gk.ETag, lastModified: gk.LastModified}
+	case scopeGeography:
+		return validators{etag: geo.ETag, lastModified: geo.LastModified}
+	default:
+		lastModified := gk.LastModified
+		if geo.LastModified.After(lastModified) {
+			lastModified = geo.LastModified
+		}
+		etag := ""
+		if gk.ETag != "" && geo.ETag != "" {
+			etag = `"` + strings.Trim(gk.ETag, `"`) + "+" + strings.Trim(geo.ETag, `"`) + `"`
+		}
+		return validators{etag: etag, lastModified: lastModified}
+	}
+}
+
+func requestValidators(r *http.Request, data *models.APIData, scope string) validators {
+	v := cacheValidators(data, scope)
+	if lang, err := requestedLanguage(r); err == nil && lang != "" && v.etag != "" {
+		v.etag = strings.TrimSuffix(v.etag, `"`) + "-" + lang + `"`
+	}
+	return v
+}
+
+func cached(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next(w, r)
+			return
+		}
+		next(conditional(w, r, requestValidators(r, apiData.Load(), scope)), r)
+	}
+}
+
+// conditional defers the cache validators until the handler writes its
+// status: only 2xx responses get them, and only those can become a 304, so
+// a request with invalid parameters still gets its error.
+func conditional(w http.ResponseWriter, r *http.Request, v validators) http.ResponseWriter {
+	return &conditionalWriter{ResponseWriter: w, r: r, v: v}
+}
+
+type conditionalWriter struct {
+	http.ResponseWriter
+	r           *http.Request
+	v           validators
+	wroteHeader bool
+	notModified bool
+}
+
+func (w *conditionalWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if status >= 200 && status < 300 && notModified(w.ResponseWriter, w.r, w.v) {
+		w.notModified = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *conditionalWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.notModified {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func notModified(w http.ResponseWriter, r *http.Request, v validators) bool {
+	if v.etag != "" {
+		w.Header().Set("ETag", v.etag)
+	}
+	if !v.lastModified.IsZero() {
+		w.Header().Set("Last-Modified", v.lastModified.Format(http.TimeFormat))
+	}
+	w.Header().Set("Cache-Control", cacheControl)
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagMatches(inm, v.etag) {
+			return false
+		}
+	} else if ims := r.Header.Get("If-Modified-Since"); ims != "" && !v.lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err != nil || v.lastModified.After(since) {
+			return false
+		}
+	} else {
+		return false
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Del("Content-Type")
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+func etagMatches(header, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
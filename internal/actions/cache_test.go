@@ -0,0 +1,145 @@
+package actions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cultpedia/internal/utils"
+)
+
+func serveWithHeaders(target string, headers map[string]string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	newAPIMux().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConditionalGet(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+	if err := reloadData(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{"/api/", "/api/questions", "/api/questions/history-first", "/api/quiz?seed=1", "/api/geography/countries", "/api/geography/countries/fr", "/api/geography/regions", "/api/geography/continents/europe"} {
+		t.Run(target, func(t *testing.T) {
+			first := serveWithHeaders(target, nil)
+			etag := first.Header().Get("ETag")
+			lastModified := first.Header().Get("Last-Modified")
+			if first.Code != http.StatusOK || etag == "" || lastModified == "" {
+				t.Fatalf("status = %d, ETag = %q, Last-Modified = %q", first.Code, etag, lastModified)
+			}
+
+			if rec := serveWithHeaders(target, map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+				t.Errorf("matching If-None-Match: status = %d, expected %d", rec.Code, http.StatusNotModified)
+			} else if rec.Body.Len() != 0 {
+				t.Error("304 response should have an empty body")
+			}
+
+			if rec := serveWithHeaders(target, map[string]string{"If-None-Match": `"other", W/` + etag}); rec.Code != http.StatusNotModified {
+				t.Errorf("weak ETag in list: status = %d, expected %d", rec.Code, http.StatusNotModified)
+			}
+
+			if rec := serveWithHeaders(target, map[string]string{"If-None-Match": `"stale"`}); rec.Code != http.StatusOK {
+				t.Errorf("stale If-None-Match: status = %d, expected %d", rec.Code, http.StatusOK)
+			}
+
+			if rec := serveWithHeaders(target, map[string]string{"If-Modified-Since": lastModified}); rec.Code != http.StatusNotModified {
+				t.Errorf("If-Modified-Since = Last-Modified: status = %d, expected %d", rec.Code, http.StatusNotModified)
+			}
+
+			old := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+			if rec := serveWithHeaders(target, map[string]string{"If-Modified-Since": old}); rec.Code != http.StatusOK {
+				t.Errorf("old If-Modified-Since: status = %d, expected %d", rec.Code, http.StatusOK)
+			}
+
+			if rec := serveWithHeaders(target, map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": lastModified}); rec.Code != http.StatusOK {
+				t.Errorf("If-None-Match takes precedence over If-Modified-Since: status = %d, expected %d", rec.Code, http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+	if err := reloadData(); err != nil {
+		t.Fatal(err)
+	}
+	etag := serveWithHeaders("/api/questions", nil).Header().Get("ETag")
+
+	for _, target := range []string{"/api/questions?limit=abc", "/api/questions/unknown", "/api/quiz?seed=1&count=0", "/api/geography/countries?lang=de"} {
+		t.Run(target, func(t *testing.T) {
+			rec := serveWithHeaders(target, map[string]string{"If-None-Match": "*"})
+			if rec.Code < 400 {
+				t.Fatalf("status = %d, expected an error", rec.Code)
+			}
+			for _, header := range []string{"ETag", "Last-Modified", "Cache-Control"} {
+				if value := rec.Header().Get(header); value != "" {
+					t.Errorf("%s = %q on a %d response", header, value, rec.Code)
+				}
+			}
+		})
+	}
+
+	if rec := serveWithHeaders("/api/questions?limit=abc", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid parameters with a matching ETag: status = %d, expected %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestETagChangesWithDataset(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+	if err := reloadData(); err != nil {
+		t.Fatal(err)
+	}
+
+	questionsETag := serveWithHeaders("/api/questions", nil).Header().Get("ETag")
+	countriesETag := serveWithHeaders("/api/geography/countries", nil).Header().Get("ETag")
+
+	writeNDJSON(t, utils.QuestionsFile, createValidDatasetQuestion("history-first"), createValidDatasetQuestion("history-second"))
+	if err := reloadData(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serveWithHeaders("/api/questions", map[string]string{"If-None-Match": questionsETag})
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == questionsETag {
+		t.Errorf("questions ETag should change with the dataset (status %d)", rec.Code)
+	}
+
+	if serveWithHeaders("/api/geography/countries", nil).Header().Get("ETag") != countriesETag {
+		t.Error("geography ETag should not change when only questions change")
+	}
+}
+
+func TestRandomQuizIsNotCached(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+	if err := reloadData(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serveWithHeaders("/api/quiz", nil)
+	if rec.Header().Get("ETag") != "" || rec.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("unseeded quiz should not be cacheable, got ETag %q and Cache-Control %q", rec.Header().Get("ETag"), rec.Header().Get("Cache-Control"))
+	}
+}
+
+func TestNegotiatedLanguageIsCachedPerLanguage(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+	if err := reloadData(); err != nil {
+		t.Fatal(err)
+	}
+
+	french := serveWithHeaders("/api/questions/history-first?lang=auto", map[string]string{"Accept-Language": "fr"})
+	english := serveWithHeaders("/api/questions/history-first?lang=auto", nil)
+	if french.Header().Get("ETag") == "" || french.Header().Get("ETag") == english.Header().Get("ETag") {
+		t.Errorf("ETag %q should depend on the negotiated language (en: %q)", french.Header().Get("ETag"), english.Header().Get("ETag"))
+	}
+
+	rec := serveWithHeaders("/api/questions/history-first?lang=auto", map[string]string{"Accept-Language": "fr", "If-None-Match": french.Header().Get("ETag")})
+	if rec.Code != http.StatusNotModified || rec.Header().Get("Vary") != "Accept-Language" {
+		t.Errorf("status = %d, Vary = %q, expected %d with Vary: Accept-Language", rec.Code, rec.Header().Get("Vary"), http.StatusNotModified)
+	}
+}
@@ -0,0 +1,579 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+const (
+	geoQuestionCapital            = "capital"
+	geoQuestionFlag               = "flag"
+	geoQuestionPopulation         = "population"
+	geoQuestionNeighbor           = "neighbor"
+	geoQuestionPopulationEstimate = "population_estimate"
+	geoQuestionArea               = "area"
+
+	geoQuestionVersion = "1.0"
+	geoDistractorCount = 3
+	// Estimates within 10% of the real figure are correct.
+	geoEstimateTolerance = 0.1
+
+	restCountriesSource = "https://restcountries.com"
+	flagIconsSource     = "https://github.com/lipis/flag-icons"
+)
+
+var geoQuestionKinds = []string{geoQuestionCapital, geoQuestionFlag, geoQuestionPopulation, geoQuestionNeighbor, geoQuestionPopulationEstimate, geoQuestionArea}
+
+var geoQuestionText = map[string]map[string]models.I18n{
+	geoQuestionCapital: {
+		"en": {Title: "Capital of {1}", Stem: "What is the capital of {1}?", Explanation: "The capital of {1} is {2}."},
+		"fr": {Title: "Capitale : {1}", Stem: "Quelle est la capitale du pays suivant : {1} ?", Explanation: "{1} a pour capitale {2}."},
+		"es": {Title: "Capital de {1}", Stem: "¿Cuál es la capital de {1}?", Explanation: "La capital de {1} es {2}."},
+	},
+	geoQuestionFlag: {
+		"en": {Title: "Flags", Stem: "Which country does this flag belong to?", Explanation: "This is the flag of {2}."},
+		"fr": {Title: "Drapeaux", Stem: "À quel pays appartient ce drapeau ?", Explanation: "Ce drapeau appartient au pays suivant : {2}."},
+		"es": {Title: "Banderas", Stem: "¿A qué país pertenece esta bandera?", Explanation: "Es la bandera de {2}."},
+	},
+	geoQuestionPopulation: {
+		"en": {Title: "Population", Stem: "Which of these countries has the largest population?", Explanation: "{1} is the most populous, with about {2} inhabitants."},
+		"fr": {Title: "Population", Stem: "Lequel de ces pays compte le plus d'habitants ?", Explanation: "Le pays le plus peuplé est le suivant : {1}, avec environ {2} habitants."},
+		"es": {Title: "Población", Stem: "¿Cuál de estos países tiene más habitantes?", Explanation: "El país más poblado es {1}, con unos {2} habitantes."},
+	},
+	geoQuestionNeighbor: {
+		"en": {Title: "Neighbors of {1}", Stem: "Which country borders {1}?", Explanation: "{2} shares a border with {1}."},
+		"fr": {Title: "Voisins : {1}", Stem: "Quel pays partage une frontière avec le pays suivant : {1} ?", Explanation: "Ces deux pays partagent une frontière : {1} et {2}."},
+		"es": {Title: "Vecinos de {1}", Stem: "¿Qué país limita con {1}?", Explanation: "{2} comparte frontera con {1}."},
+	},
+	geoQuestionPopulationEstimate: {
+		"en": {Title: "Population of {1}", Stem: "How many people live in {1}?", Explanation: "{1} has about {2} inhabitants."},
+		"fr": {Title: "Population : {1}", Stem: "Combien d'habitants compte le pays suivant : {1} ?", Explanation: "{1} compte environ {2} habitants."},
+		"es": {Title: "Población de {1}", Stem: "¿Cuántos habitantes tiene {1}?", Explanation: "{1} tiene unos {2} habitantes."},
+	},
+	geoQuestionArea: {
+		"en": {Title: "Area of {1}", Stem: "What is the area of {1} in km²?", Explanation: "{1} covers about {2} km²."},
+		"fr": {Title: "Superficie : {1}", Stem: "Quelle est la superficie en km² du pays suivant : {1} ?", Explanation: "{1} a une superficie d'environ {2} km²."},
+		"es": {Title: "Superficie de {1}", Stem: "¿Cuál es la superficie de {1} en km²?", Explanation: "{1} tiene una superficie de unos {2} km²."},
+	},
+}
+
+var geoQuestionLanguages = []string{"en", "fr", "es"}
+
+type geoQuestionTask struct {
+	kind    string
+	country int
+}
+
+type geoQuestionGenerator struct {
+	countries []models.Country
+	alpha3    map[string]int
+	rng       *rand.Rand
+}
+
+func GenerateGeographyQuestions(kinds []string, count int, seed int64) ([]models.Question, error) {
+	countries, err := utils.LoadCountries()
+	if err != nil {
+		return nil, fmt.Errorf("error loading countries: %w", err)
+	}
+	return generateGeographyQuestions(countries, kinds, count, seed)
+}
+
+func WriteQuestions(w io.Writer, questions []models.Question) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, q := range questions {
+		if err := encoder.Encode(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ParseGeoQuestionKinds(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return geoQuestionKinds, nil
+	}
+	var kinds []string
+	for _, kind := range strings.Split(raw, ",") {
+		kind = strings.TrimSpace(kind)
+		if !utils.Contains(geoQuestionKinds, kind) {
+			return nil, fmt.Errorf("kind must be one of: %s (got '%s')", strings.Join(geoQuestionKinds, ", "), kind)
+		}
+		if !utils.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds, nil
+}
+
+// generateGeographyQuestions returns the quiz of seed with opaque slugs: the
+// question slug is made of the seed, the kinds and the position in the quiz,
+// and answers are lettered once shuffled, so that neither names the correct
+// country. findGeographyQuestion generates the question again from its slug.
+func generateGeographyQuestions(countries []models.Country, kinds []string, count int, seed int64) ([]models.Question, error) {
+	mask, err := geoKindMask(kinds)
+	if err != nil {
+		return nil, err
+	}
+	questions := newGeoQuestionGenerator(countries, seed).generate(geoMaskKinds(mask), count)
+	for i := range questions {
+		questions[i].Slug = geoQuestionSlug(seed, mask, i+1)
+		for j := range questions[i].Answers {
+			questions[i].Answers[j].Slug = string(rune('a' + j))
+		}
+	}
+	return questions, nil
+}
+
+// findGeographyQuestion returns the generated question behind a slug of
+// generateGeographyQuestions, as long as the countries have not changed.
+func findGeographyQuestion(countries []models.Country, slug string) (models.Question, bool) {
+	parts := strings.Split(strings.TrimPrefix(slug, "geography-"), "-")
+	if len(parts) != 3 || !strings.HasPrefix(slug, "geography-") {
+		return models.Question{}, false
+	}
+	seed, err := strconv.ParseUint(parts[0], 36, 64)
+	if err != nil {
+		return models.Question{}, false
+	}
+	mask, err := strconv.ParseUint(parts[1], 10, len(geoQuestionKinds))
+	if err != nil || mask == 0 {
+		return models.Question{}, false
+	}
+	position, err := strconv.Atoi(parts[2])
+	if err != nil || position < 1 || position > len(countries)*len(geoQuestionKinds) {
+		return models.Question{}, false
+	}
+
+	questions, err := generateGeographyQuestions(countries, geoMaskKinds(mask), position, int64(seed))
+	if err != nil || len(questions) < position || questions[position-1].Slug != slug {
+		return models.Question{}, false
+	}
+	return questions[position-1], true
+}
+
+func geoQuestionSlug(seed int64, mask uint64, position int) string {
+	return fmt.Sprintf("geography-%s-%d-%d", strconv.FormatUint(uint64(seed), 36), mask, position)
+}
+
+// geoKindMask sets the bit of each kind in geoQuestionKinds, so that a quiz
+// does not depend on the order its kinds were given in.
+func geoKindMask(kinds []string) (uint64, error) {
+	var mask uint64
+	for _, kind := range kinds {
+		i := slices.Index(geoQuestionKinds, kind)
+		if i < 0 {
+			return 0, fmt.Errorf("unknown geography question kind '%s'", kind)
+		}
+		mask |= 1 << i
+	}
+	if mask == 0 {
+		return 0, fmt.Errorf("at least one geography question kind is required")
+	}
+	return mask, nil
+}
+
+func geoMaskKinds(mask uint64) []string {
+	var kinds []string
+	for i, kind := range geoQuestionKinds {
+		if mask&(1<<i) != 0 {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+func newGeoQuestionGenerator(countries []models.Country, seed int64) *geoQuestionGenerator {
+	gen := &geoQuestionGenerator{
+		countries: countries,
+		alpha3:    make(map[string]int, len(countries)),
+		rng:       rand.New(rand.NewSource(seed)),
+	}
+	for i, c := range countries {
+		gen.alpha3[strings.ToLower(c.ISOAlpha3)] = i
+	}
+	return gen
+}
+
+// generate returns up to count questions of the given kinds, or all of them
+// for a count of 0. Their slugs describe the question, and the slugs of their
+// answers are the country slugs.
+func (gen *geoQuestionGenerator) generate(kinds []string, count int) []models.Question {
+	var tasks []geoQuestionTask
+	for _, kind := range kinds {
+		for i := range gen.countries {
+			tasks = append(tasks, geoQuestionTask{kind: kind, country: i})
+		}
+	}
+	gen.rng.Shuffle(len(tasks), func(i, j int) { tasks[i], tasks[j] = tasks[j], tasks[i] })
+
+	var questions []models.Question
+	seen := make(map[string]bool)
+	for _, task := range tasks {
+		if count > 0 && len(questions) >= count {
+			break
+		}
+		q, ok := gen.question(task)
+		if !ok || seen[q.Slug] {
+			continue
+		}
+		seen[q.Slug] = true
+		questions = append(questions, q)
+	}
+	return questions
+}
+
+func (gen *geoQuestionGenerator) question(task geoQuestionTask) (models.Question, bool) {
+	switch task.kind {
+	case geoQuestionCapital:
+		return gen.capitalQuestion(task.country)
+	case geoQuestionFlag:
+		return gen.flagQuestion(task.country)
+	case geoQuestionPopulation:
+		return gen.populationQuestion(task.country)
+	case geoQuestionNeighbor:
+		return gen.neighborQuestion(task.country)
+	case geoQuestionPopulationEstimate:
+		return gen.estimateQuestion(geoQuestionPopulationEstimate, task.country, "geography-population-", "", func(c models.Country) float64 { return math.Round(float64(c.Population)) }, func(v float64, lang string) string { return formatPopulation(int64(v), lang) })
+	case geoQuestionArea:
+		return gen.estimateQuestion(geoQuestionArea, task.country, "geography-area-", "km²", func(c models.Country) float64 { return roundArea(c.AreaKm2) }, formatArea)
+	}
+	return models.Question{}, false
+}
+
+func (gen *geoQuestionGenerator) capitalQuestion(i int) (models.Question, bool) {
+	country := gen.countries[i]
+	if !hasAllLanguages(country.Name) || !hasAllLanguages(country.Capital) {
+		return models.Question{}, false
+	}
+
+	distractors := gen.distractors(country, func(c models.Country) bool {
+		return c.Slug != country.Slug && hasAllLanguages(c.Name) && hasAllLanguages(c.Capital) &&
+			utils.FoldText(c.Capital["en"]) != utils.FoldText(country.Capital["en"])
+	})
+	if distractors == nil {
+		return models.Question{}, false
+	}
+
+	q := gen.newQuestion(geoQuestionCapital, "geography-capital-"+country.Slug, "capitals", countryDifficulty(country), restCountriesSource)
+	for _, lang := range geoQuestionLanguages {
+		q.I18n[lang] = formatGeoText(geoQuestionCapital, lang, countryInText(country, lang), country.Capital[lang])
+	}
+	q.Answers = gen.answers(country, distractors, func(c models.Country) map[string]string { return c.Capital })
+	return q, true
+}
+
+// flagQuestion shows the flag through Question.Flag, the same SVG as
+// /api/geography/flags/{code}: emoji flags do not render everywhere.
+func (gen *geoQuestionGenerator) flagQuestion(i int) (models.Question, bool) {
+	country := gen.countries[i]
+	if !hasAllLanguages(country.Name) || country.Flag == "" {
+		return models.Question{}, false
+	}
+
+	distractors := gen.distractors(country, func(c models.Country) bool {
+		return c.Slug != country.Slug && hasAllLanguages(c.Name)
+	})
+	if distractors == nil {
+		return models.Question{}, false
+	}
+
+	q := gen.newQuestion(geoQuestionFlag, "geography-flag-"+country.Slug, "countries", countryDifficulty(country), flagIconsSource)
+	for _, lang := range geoQuestionLanguages {
+		q.I18n[lang] = formatGeoText(geoQuestionFlag, lang, "", countryInText(country, lang))
+	}
+	q.Flag = strings.ToLower(country.Flag)
+	q.Answers = gen.answers(country, distractors, func(c models.Country) map[string]string { return c.Name })
+	return q, true
+}
+
+func (gen *geoQuestionGenerator) populationQuestion(i int) (models.Question, bool) {
+	anchor := gen.countries[i]
+	if !hasAllLanguages(anchor.Name) || anchor.Population <= 0 {
+		return models.Question{}, false
+	}
+
+	distractors := gen.distractors(anchor, func(c models.Country) bool {
+		return c.Slug != anchor.Slug && hasAllLanguages(c.Name) && c.Population > 0 && c.Population != anchor.Population
+	})
+	if distractors == nil {
+		return models.Question{}, false
+	}
+
+	options := append([]models.Country{anchor}, distractors...)
+	sort.Slice(options, func(a, b int) bool { return options[a].Population > options[b].Population })
+	if options[0].Population == options[1].Population {
+		return models.Question{}, false
+	}
+	correct := options[0]
+
+	slugs := make([]string, len(options))
+	for j, c := range options {
+		slugs[j] = c.Slug
+	}
+	sort.Strings(slugs)
+
+	q := gen.newQuestion(geoQuestionPopulation, "geography-most-populous-"+strings.Join(slugs, "-"), "countries", "intermediate", restCountriesSource)
+	for _, lang := range geoQuestionLanguages {
+		q.I18n[lang] = formatGeoText(geoQuestionPopulation, lang, countryInText(correct, lang), formatPopulation(correct.Population, lang))
+	}
+	q.Answers = gen.answers(correct, options[1:], func(c models.Country) map[string]string { return c.Name })
+	return q, true
+}
+
+func (gen *geoQuestionGenerator) neighborQuestion(i int) (models.Question, bool) {
+	country := gen.countries[i]
+	if !hasAllLanguages(country.Name) {
+		return models.Question{}, false
+	}
+
+	neighbors := make(map[string]bool, len(country.Neighbors))
+	var candidates []models.Country
+	for _, code := range country.Neighbors {
+		if j, ok := gen.alpha3[strings.ToLower(code)]; ok && hasAllLanguages(gen.countries[j].Name) {
+			neighbors[gen.countries[j].Slug] = true
+			candidates = append(candidates, gen.countries[j])
+		}
+	}
+	if len(candidates) == 0 {
+		return models.Question{}, false
+	}
+	correct := candidates[gen.rng.Intn(len(candidates))]
+
+	distractors := gen.distractors(country, func(c models.Country) bool {
+		return c.Slug != country.Slug && !neighbors[c.Slug] && hasAllLanguages(c.Name)
+	})
+	if distractors == nil {
+		return models.Question{}, false
+	}
+
+	q := gen.newQuestion(geoQuestionNeighbor, "geography-neighbor-"+country.Slug+"-"+correct.Slug, "countries", "advanced", restCountriesSource)
+	for _, lang := range geoQuestionLanguages {
+		q.I18n[lang] = formatGeoText(geoQuestionNeighbor, lang, countryInText(country, lang), countryInText(correct, lang))
+	}
+	q.Answers = gen.answers(correct, distractors, func(c models.Country) map[string]string { return c.Name })
+	return q, true
+}
+
+func (gen *geoQuestionGenerator) estimateQuestion(kind string, i int, slugPrefix, unit string, value func(models.Country) float64, format func(float64, string) string) (models.Question, bool) {
+	country := gen.countries[i]
+	expected := value(country)
+	if !hasAllLanguages(country.Name) || expected <= 0 {
+		return models.Question{}, false
+	}
+
+	q := gen.newQuestion(kind, slugPrefix+country.Slug, "countries", "advanced", restCountriesSource)
+	for _, lang := range geoQuestionLanguages {
+		q.I18n[lang] = formatGeoText(kind, lang, countryInText(country, lang), format(expected, lang))
+	}
+	q.Qtype = "numeric"
+	q.Scoring = "partial"
+	q.ShuffleAnswers = false
+	q.Answers = []models.Answer{}
+	q.Numeric = &models.NumericAnswer{Value: expected, Unit: unit, RelativeTolerance: geoEstimateTolerance}
+	return q, true
+}
+
+func (gen *geoQuestionGenerator) distractors(country models.Country, eligible func(models.Country) bool) []models.Country {
+	var region, continent, world []models.Country
+	for _, c := range gen.countries {
+		if !eligible(c) {
+			continue
+		}
+		switch {
+		case c.Region != "" && c.Region == country.Region:
+			region = append(region, c)
+		case c.Continent != "" && c.Continent == country.Continent:
+			continent = append(continent, c)
+		default:
+			world = append(world, c)
+		}
+	}
+
+	picked := make([]models.Country, 0, geoDistractorCount)
+	for _, pool := range [][]models.Country{region, continent, world} {
+		gen.rng.Shuffle(len(pool), func(a, b int) { pool[a], pool[b] = pool[b], pool[a] })
+		for _, c := range pool {
+			if len(picked) == geoDistractorCount {
+				return picked
+			}
+			picked = append(picked, c)
+		}
+	}
+	if len(picked) < geoDistractorCount {
+		return nil
+	}
+	return picked
+}
+
+func (gen *geoQuestionGenerator) answers(correct models.Country, distractors []models.Country, label func(models.Country) map[string]string) []models.Answer {
+	answers := make([]models.Answer, 0, len(distractors)+1)
+	for j, c := range append([]models.Country{correct}, distractors...) {
+		labels := label(c)
+		answer := models.Answer{Slug: c.Slug, IsCorrect: j == 0, I18n: make(map[string]models.Label, len(geoQuestionLanguages))}
+		for _, lang := range geoQuestionLanguages {
+			answer.I18n[lang] = models.Label{Label: labels[lang]}
+		}
+		answers = append(answers, answer)
+	}
+	gen.rng.Shuffle(len(answers), func(a, b int) { answers[a], answers[b] = answers[b], answers[a] })
+	return answers
+}
+
+func (gen *geoQuestionGenerator) newQuestion(kind, slug, subtheme, difficulty, source string) models.Question {
+	seconds := 10
+	switch kind {
+	case geoQuestionPopulation, geoQuestionNeighbor:
+		seconds = 15
+	case geoQuestionPopulationEstimate, geoQuestionArea:
+		seconds = 20
+	}
+	return models.Question{
+		Kind:             "question",
+		Version:          geoQuestionVersion,
+		Slug:             slug,
+		Theme:            models.Theme{Slug: "geography"},
+		Subthemes:        []models.Theme{{Slug: subtheme}},
+		Qtype:            "single_choice",
+		Difficulty:       difficulty,
+		EstimatedSeconds: seconds,
+		Points:           difficultyPoints(difficulty),
+		ShuffleAnswers:   true,
+		I18n:             make(map[string]models.I18n, len(geoQuestionLanguages)),
+		Sources:          []string{source},
+	}
+}
+
+func formatGeoText(kind, lang, first, second string) models.I18n {
+	text := geoQuestionText[kind][lang]
+	replacer := strings.NewReplacer("{1}", first, "{2}", second)
+	return models.I18n{
+		Title:       capitalize(replacer.Replace(text.Title)),
+		Stem:        capitalize(replacer.Replace(text.Stem)),
+		Explanation: capitalize(replacer.Replace(text.Explanation)),
+	}
+}
+
+// englishArticleNames take "the" in an English sentence, like the plural
+// names and the names ending in Islands or Republic that countryInText
+// recognizes on its own.
+var englishArticleNames = map[string]bool{
+	"Bahamas":     true,
+	"Comoros":     true,
+	"Gambia":      true,
+	"Maldives":    true,
+	"Netherlands": true,
+	"Philippines": true,
+	"Seychelles":  true,
+}
+
+// countryInText returns the name of a country as written within a sentence:
+// "the Philippines" rather than "Philippines" in English. Names with a comma
+// or parentheses are ISO-style names that read badly either way, and are
+// left as they are.
+func countryInText(c models.Country, lang string) string {
+	name := c.Name[lang]
+	if lang != "en" || strings.ContainsAny(name, "(,") || strings.Contains(name, " the ") {
+		return name
+	}
+	if englishArticleNames[name] || strings.HasPrefix(name, "United ") || strings.Contains(name, "Republic") ||
+		strings.HasSuffix(name, "Islands") || strings.HasSuffix(name, "Territory") || strings.HasSuffix(name, "Territories") {
+		return "the " + name
+	}
+	return name
+}
+
+// capitalize makes a text that starts with a country name start like a
+// sentence again: "The Philippines has…".
+func capitalize(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	if size == 0 {
+		return text
+	}
+	return string(unicode.ToUpper(r)) + text[size:]
+}
+
+func countryDifficulty(c models.Country) string {
+	switch {
+	case c.Population >= 50_000_000:
+		return "beginner"
+	case c.Population >= 5_000_000:
+		return "intermediate"
+	default:
+		return "advanced"
+	}
+}
+
+func difficultyPoints(difficulty string) float64 {
+	switch difficulty {
+	case "beginner":
+		return 0.5
+	case "intermediate":
+		return 1
+	default:
+		return 1.5
+	}
+}
+
+func hasAllLanguages(translations map[string]string) bool {
+	for _, lang := range geoQuestionLanguages {
+		if strings.TrimSpace(translations[lang]) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func formatPopulation(n int64, lang string) string {
+	separator := ","
+	switch lang {
+	case "fr":
+		separator = "\u202f"
+	case "es":
+		separator = "."
+	}
+
+	digits := strconv.FormatInt(n, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(separator)
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+// roundArea keeps two decimals below 100 km², so that the smallest countries,
+// such as the Vatican at 0.44 km², still get an area question.
+func roundArea(km2 float64) float64 {
+	if km2 >= 100 {
+		return math.Round(km2)
+	}
+	return math.Round(km2*100) / 100
+}
+
+// formatArea writes areas of 100 km² and more as whole numbers grouped like
+// populations, and smaller ones with their decimals.
+func formatArea(km2 float64, lang string) string {
+	if km2 >= 100 {
+		return formatPopulation(int64(math.Round(km2)), lang)
+	}
+	text := strconv.FormatFloat(km2, 'f', -1, 64)
+	if lang == "fr" || lang == "es" {
+		text = strings.Replace(text, ".", ",", 1)
+	}
+	return text
+}
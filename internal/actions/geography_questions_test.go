@@ -0,0 +1,386 @@
+package actions
+
+import (
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cultpedia/internal/checks"
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+func createTestCountry(slug, alpha3, region, continent, name, capital string, population int64, neighbors ...string) models.Country {
+	return models.Country{
+		Slug:         slug,
+		ISOAlpha2:    strings.ToUpper(slug),
+		ISOAlpha3:    alpha3,
+		Name:         map[string]string{"en": name, "fr": name + " (fr)", "es": name + " (es)"},
+		OfficialName: map[string]string{"en": name, "fr": name, "es": name},
+		Capital:      map[string]string{"en": capital, "fr": capital + " (fr)", "es": capital + " (es)"},
+		Region:       region,
+		Continent:    continent,
+		Flag:         slug,
+		Population:   population,
+		Neighbors:    neighbors,
+	}
+}
+
+func testGeographyCountries() []models.Country {
+	countries := []models.Country{
+		createTestCountry("fr", "FRA", "western_europe", "europe", "France", "Paris", 68000000, "BEL", "DEU", "CHE"),
+		createTestCountry("be", "BEL", "western_europe", "europe", "Belgium", "Brussels", 11000000, "FRA", "DEU", "NLD"),
+		createTestCountry("de", "DEU", "western_europe", "europe", "Germany", "Berlin", 83000000, "FRA", "BEL", "NLD", "CHE"),
+		createTestCountry("nl", "NLD", "western_europe", "europe", "Netherlands", "Amsterdam", 17000000, "BEL", "DEU"),
+		createTestCountry("ch", "CHE", "western_europe", "europe", "Switzerland", "Bern", 8000000, "FRA", "DEU"),
+		createTestCountry("es", "ESP", "southern_europe", "europe", "Spain", "Madrid", 47000000, "PRT"),
+		createTestCountry("pt", "PRT", "southern_europe", "europe", "Portugal", "Lisbon", 10000000, "ESP"),
+		createTestCountry("jp", "JPN", "eastern_asia", "asia", "Japan", "Tokyo", 125000000),
+		createTestCountry("kr", "KOR", "eastern_asia", "asia", "South Korea", "Seoul", 51000000),
+		createTestCountry("cn", "CHN", "eastern_asia", "asia", "China", "Beijing", 1400000000, "KOR"),
+	}
+	for i, area := range []float64{551695, 30528, 357114, 41850, 41284, 505990, 92212, 377975, 100210.4} {
+		countries[i].AreaKm2 = area
+	}
+	return countries
+}
+
+func correctAnswer(q models.Question) models.Answer {
+	for _, a := range q.Answers {
+		if a.IsCorrect {
+			return a
+		}
+	}
+	return models.Answer{}
+}
+
+func TestGenerateGeographyQuestions(t *testing.T) {
+	countries := testGeographyCountries()
+	byRegion := make(map[string]string)
+	for _, c := range countries {
+		byRegion[c.Slug] = c.Region
+	}
+
+	questions := newGeoQuestionGenerator(countries, 42).generate(geoQuestionKinds, 0)
+	if err := checks.ValidateQuestionSet(questions); err != nil {
+		t.Fatalf("generated questions are invalid: %v", err)
+	}
+
+	kinds := make(map[string]int)
+	for _, q := range questions {
+		correct := correctAnswer(q)
+		for _, lang := range []string{"en", "fr", "es"} {
+			if q.I18n[lang].Stem == "" || (q.Qtype != "numeric" && correct.I18n[lang].Label == "") {
+				t.Errorf("%s: missing %s text", q.Slug, lang)
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(q.Slug, "geography-capital-"):
+			kinds[geoQuestionCapital]++
+			country := strings.TrimPrefix(q.Slug, "geography-capital-")
+			if correct.Slug != country {
+				t.Errorf("%s: correct answer is %s", q.Slug, correct.Slug)
+			}
+			if byRegion[country] == "western_europe" {
+				for _, a := range q.Answers {
+					if byRegion[a.Slug] != "western_europe" {
+						t.Errorf("%s: distractor %s should come from the same region", q.Slug, a.Slug)
+					}
+				}
+			}
+		case strings.HasPrefix(q.Slug, "geography-flag-"):
+			kinds[geoQuestionFlag]++
+			if correct.Slug != strings.TrimPrefix(q.Slug, "geography-flag-") {
+				t.Errorf("%s: correct answer is %s", q.Slug, correct.Slug)
+			}
+			if q.Flag != correct.Slug {
+				t.Errorf("%s: flag = %q, expected %q", q.Slug, q.Flag, correct.Slug)
+			}
+		case strings.HasPrefix(q.Slug, "geography-most-populous-"):
+			kinds[geoQuestionPopulation]++
+			var max models.Country
+			for _, a := range q.Answers {
+				for _, c := range countries {
+					if c.Slug == a.Slug && c.Population > max.Population {
+						max = c
+					}
+				}
+			}
+			if correct.Slug != max.Slug {
+				t.Errorf("%s: correct answer is %s, expected %s", q.Slug, correct.Slug, max.Slug)
+			}
+		case strings.HasPrefix(q.Slug, "geography-neighbor-"):
+			kinds[geoQuestionNeighbor]++
+			country := strings.Split(strings.TrimPrefix(q.Slug, "geography-neighbor-"), "-")[0]
+			var neighbors []string
+			for _, c := range countries {
+				if c.Slug == country {
+					neighbors = c.Neighbors
+				}
+			}
+			for _, a := range q.Answers {
+				isNeighbor := false
+				for _, code := range neighbors {
+					if strings.EqualFold(code, countryAlpha3(countries, a.Slug)) {
+						isNeighbor = true
+					}
+				}
+				if isNeighbor != a.IsCorrect {
+					t.Errorf("%s: answer %s neighbor=%v correct=%v", q.Slug, a.Slug, isNeighbor, a.IsCorrect)
+				}
+			}
+		case strings.HasPrefix(q.Slug, "geography-population-"), strings.HasPrefix(q.Slug, "geography-area-"):
+			kind, expected := geoQuestionPopulationEstimate, float64(countryBySlug(countries, strings.TrimPrefix(q.Slug, "geography-population-")).Population)
+			if strings.HasPrefix(q.Slug, "geography-area-") {
+				kind, expected = geoQuestionArea, math.Round(countryBySlug(countries, strings.TrimPrefix(q.Slug, "geography-area-")).AreaKm2)
+			}
+			kinds[kind]++
+			if q.Qtype != "numeric" || q.Numeric == nil || q.Numeric.Value != expected || q.Numeric.RelativeTolerance != geoEstimateTolerance {
+				t.Errorf("%s: unexpected numeric answer %+v", q.Slug, q.Numeric)
+			}
+		default:
+			t.Errorf("unexpected slug %s", q.Slug)
+		}
+	}
+
+	for _, kind := range geoQuestionKinds {
+		if kinds[kind] == 0 {
+			t.Errorf("no %s questions generated", kind)
+		}
+	}
+	if kinds[geoQuestionNeighbor] != 8 {
+		t.Errorf("expected 8 neighbor questions (one per country with neighbors), got %d", kinds[geoQuestionNeighbor])
+	}
+	if kinds[geoQuestionArea] != 9 {
+		t.Errorf("expected 9 area questions (one per country with an area), got %d", kinds[geoQuestionArea])
+	}
+}
+
+func TestGeographyQuestionSlugsAreOpaque(t *testing.T) {
+	countries := testGeographyCountries()
+
+	questions, err := generateGeographyQuestions(countries, []string{geoQuestionFlag, geoQuestionCapital}, 0, 42)
+	if err != nil {
+		t.Fatalf("generateGeographyQuestions() returned unexpected error: %v", err)
+	}
+	if err := checks.ValidateQuestionSet(questions); err != nil {
+		t.Fatalf("generated questions are invalid: %v", err)
+	}
+	reordered, _ := generateGeographyQuestions(countries, []string{geoQuestionCapital, geoQuestionFlag}, 0, 42)
+	if !reflect.DeepEqual(questions, reordered) {
+		t.Error("the order of the kinds should not change the quiz")
+	}
+
+	for _, q := range questions {
+		for _, c := range countries {
+			if strings.HasSuffix(q.Slug, "-"+c.Slug) || strings.Contains(q.Slug, "-"+c.Slug+"-") {
+				t.Errorf("%s: slug names country %s", q.Slug, c.Slug)
+			}
+		}
+		for i, a := range q.Answers {
+			if expected := string(rune('a' + i)); a.Slug != expected {
+				t.Errorf("%s: answer slug %q, expected %q", q.Slug, a.Slug, expected)
+			}
+		}
+
+		found, ok := findGeographyQuestion(countries, q.Slug)
+		if !ok || !reflect.DeepEqual(found, q) {
+			t.Errorf("findGeographyQuestion(%s) = %+v, %v", q.Slug, found, ok)
+		}
+	}
+
+	for _, slug := range []string{"geography-capital-fr", "geography-16-0-1", "geography-16-64-1", "geography-16-3-0", "geography-16-3-999", "history-16-3-1", "geography-16-3-1-2"} {
+		if _, ok := findGeographyQuestion(countries, slug); ok {
+			t.Errorf("findGeographyQuestion(%s) should not find a question", slug)
+		}
+	}
+}
+
+func TestCountryInText(t *testing.T) {
+	tests := map[string]string{
+		"France":                       "France",
+		"Philippines":                  "the Philippines",
+		"United States of America":     "the United States of America",
+		"Czech Republic":               "the Czech Republic",
+		"Cayman Islands":               "the Cayman Islands",
+		"Christmas Island":             "Christmas Island",
+		"Korea (Republic of)":          "Korea (Republic of)",
+		"Tanzania, United Republic of": "Tanzania, United Republic of",
+		"South Georgia and the South Sandwich Islands": "South Georgia and the South Sandwich Islands",
+	}
+	for name, expected := range tests {
+		c := models.Country{Name: map[string]string{"en": name, "fr": name}}
+		if got := countryInText(c, "en"); got != expected {
+			t.Errorf("countryInText(%q) = %q, expected %q", name, got, expected)
+		}
+		if got := countryInText(c, "fr"); got != name {
+			t.Errorf("countryInText(%q, fr) = %q, expected the name unchanged", name, got)
+		}
+	}
+
+	text := formatGeoText(geoQuestionPopulation, "en", "the Philippines", "112,000,000")
+	if text.Stem != "Which of these countries has the largest population?" || !strings.HasPrefix(text.Explanation, "The Philippines is") {
+		t.Errorf("formatGeoText() = %+v", text)
+	}
+}
+
+func countryBySlug(countries []models.Country, slug string) models.Country {
+	for _, c := range countries {
+		if c.Slug == slug {
+			return c
+		}
+	}
+	return models.Country{}
+}
+
+func countryAlpha3(countries []models.Country, slug string) string {
+	return countryBySlug(countries, slug).ISOAlpha3
+}
+
+func TestGenerateGeographyQuestionsDeterministic(t *testing.T) {
+	countries := testGeographyCountries()
+
+	first, _ := generateGeographyQuestions(countries, geoQuestionKinds, 5, 7)
+	second, _ := generateGeographyQuestions(countries, geoQuestionKinds, 5, 7)
+	if len(first) != 5 {
+		t.Fatalf("expected 5 questions, got %d", len(first))
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("same seed should generate the same questions")
+	}
+
+	if _, err := generateGeographyQuestions(countries, []string{"currency"}, 5, 7); err == nil {
+		t.Error("unknown kind should return an error")
+	}
+}
+
+func TestParseGeoQuestionKinds(t *testing.T) {
+	kinds, err := ParseGeoQuestionKinds("flag, capital,flag")
+	if err != nil || !reflect.DeepEqual(kinds, []string{geoQuestionFlag, geoQuestionCapital}) {
+		t.Errorf("ParseGeoQuestionKinds() = %v, %v", kinds, err)
+	}
+	if kinds, _ := ParseGeoQuestionKinds(""); len(kinds) != len(geoQuestionKinds) {
+		t.Errorf("empty kind should select all kinds, got %v", kinds)
+	}
+	if _, err := ParseGeoQuestionKinds("currency"); err == nil {
+		t.Error("unknown kind should return an error")
+	}
+}
+
+func TestHandleQuestionFlag(t *testing.T) {
+	setTestQuestions(t)
+	apiData.Load().Countries = testGeographyCountries()
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(utils.FlagsSVGDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range apiData.Load().Countries {
+		svg := `<svg xmlns="http://www.w3.org/2000/svg" id="flag-icons-` + c.Flag + `" viewBox="0 0 640 480"></svg>`
+		if err := os.WriteFile(filepath.Join(utils.FlagsSVGDir, c.Flag+".svg"), []byte(svg), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	quiz, err := generateGeographyQuestions(apiData.Load().Countries, []string{geoQuestionFlag}, 1, 5)
+	if err != nil || len(quiz) != 1 {
+		t.Fatalf("generateGeographyQuestions() = %v, %v", quiz, err)
+	}
+	rec := serveTestRequest(http.MethodGet, questionFlagURL(quiz[0]), "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/svg+xml" {
+		t.Fatalf("status = %d, Content-Type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if strings.Contains(rec.Body.String(), "flag-icons") || strings.Contains(rec.Header().Get("ETag"), quiz[0].Flag) {
+		t.Errorf("the flag should not name the country: %s %s", rec.Header().Get("ETag"), rec.Body.String())
+	}
+
+	for _, target := range []string{"/api/questions/history-q1/flag", "/api/questions/geography-5-1-1/flag", "/api/questions/unknown/flag"} {
+		if rec := serveTestRequest(http.MethodGet, target, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, expected %d", target, rec.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestGeoQuestionFormatting(t *testing.T) {
+	tests := map[string]string{"en": "68,042,591", "fr": "68\u202f042\u202f591", "es": "68.042.591"}
+	for lang, expected := range tests {
+		if got := formatPopulation(68042591, lang); got != expected {
+			t.Errorf("formatPopulation(%s) = %q, expected %q", lang, got, expected)
+		}
+	}
+	if got := formatPopulation(591, "en"); got != "591" {
+		t.Errorf("formatPopulation(591) = %q", got)
+	}
+
+	areas := []struct {
+		km2      float64
+		lang     string
+		expected string
+	}{
+		{551695, "en", "551,695"},
+		{551695.4, "fr", "551\u202f695"},
+		{0.44, "en", "0.44"},
+		{roundArea(0.4449), "es", "0,44"},
+		{61.2, "fr", "61,2"},
+	}
+	for _, tt := range areas {
+		if got := formatArea(tt.km2, tt.lang); got != tt.expected {
+			t.Errorf("formatArea(%v, %s) = %q, expected %q", tt.km2, tt.lang, got, tt.expected)
+		}
+	}
+}
+
+func TestHandleGeographyQuiz(t *testing.T) {
+	setTestQuestions(t)
+	apiData.Load().Countries = testGeographyCountries()
+
+	rec := serveTestRequest(http.MethodGet, "/api/geography/quiz?count=4&seed=9&kind=capital", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, expected %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	response := decodeQuiz(t, rec)
+	if response.Seed != 9 || response.Count != 4 {
+		t.Errorf("unexpected quiz: seed=%d count=%d", response.Seed, response.Count)
+	}
+	for _, q := range response.Data {
+		if !strings.HasPrefix(q.Slug, "geography-9-1-") {
+			t.Errorf("unexpected question %s", q.Slug)
+		}
+	}
+
+	q := response.Data[0]
+	answer := serveTestRequest(http.MethodPost, "/api/questions/"+q.Slug+"/answer", `{"answer":"`+correctAnswer(q).Slug+`"}`)
+	if answer.Code != http.StatusOK || !strings.Contains(answer.Body.String(), `"correct":true`) {
+		t.Errorf("answering %s: status = %d: %s", q.Slug, answer.Code, answer.Body.String())
+	}
+
+	again := serveTestRequest(http.MethodGet, "/api/geography/quiz?count=4&seed=9&kind=capital", "")
+	if again.Body.String() != rec.Body.String() {
+		t.Error("same seed should return the same quiz")
+	}
+
+	player := serveTestRequest(http.MethodGet, "/api/geography/quiz?seed=9&view=player&lang=fr", "")
+	if strings.Contains(player.Body.String(), "is_correct") || strings.Contains(player.Body.String(), "explanation") {
+		t.Error("player view should hide answers")
+	}
+
+	flags := serveTestRequest(http.MethodGet, "/api/geography/quiz?seed=9&kind=flag&view=player", "")
+	for _, q := range decodeQuiz(t, flags).Data {
+		if q.Flag != "" {
+			t.Errorf("%s: player view should not send the flag code %q", q.Slug, q.Flag)
+		}
+	}
+	if !strings.Contains(flags.Body.String(), `"flag_url":"/api/questions/geography-9-2-1/flag"`) {
+		t.Errorf("player view should send the flag URL: %s", flags.Body.String())
+	}
+
+	for _, target := range []string{"/api/geography/quiz?kind=currency", "/api/geography/quiz?count=0", "/api/geography/quiz?seed=x"} {
+		if rec := serveTestRequest(http.MethodGet, target, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, expected %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
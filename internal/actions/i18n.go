@@ -0,0 +1,301 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cultpedia/internal/models"
+)
+
+const (
+	defaultLang       = "en"
+	allLanguages      = "all"
+	negotiateLanguage = "auto"
+)
+
+var supportedLanguages = []string{"en", "fr", "es"}
+
+// requestedLanguage returns the language asked for with ?lang=, or "" for
+// the full multi-language objects. ?lang=auto negotiates it through
+// Accept-Language, falling back to en. Without it the header does not change
+// the shape of a response.
+func requestedLanguage(r *http.Request) (string, error) {
+	lang := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("lang")))
+	switch {
+	case lang == "" || lang == allLanguages:
+		return "", nil
+	case lang == negotiateLanguage:
+		return parseAcceptLanguage(r.Header.Get("Accept-Language")), nil
+	case !isSupportedLanguage(lang):
+		return "", fmt.Errorf("lang must be one of: %s, %s, %s (got '%s')", strings.Join(supportedLanguages, ", "), negotiateLanguage, allLanguages, lang)
+	}
+	return lang, nil
+}
+
+// preferredLanguage is requestedLanguage negotiating through Accept-Language
+// when ?lang= is not given, for responses that always use a single language.
+func preferredLanguage(r *http.Request) (string, error) {
+	lang, err := requestedLanguage(r)
+	if err != nil || lang != "" || r.URL.Query().Get("lang") != "" {
+		return lang, err
+	}
+	return parseAcceptLanguage(r.Header.Get("Accept-Language")), nil
+}
+
+// varyOnLanguage tells caches that Accept-Language picked the language of the
+// response, when ?lang= left the choice to it.
+func varyOnLanguage(w http.ResponseWriter, r *http.Request) {
+	lang := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("lang")))
+	if lang == negotiateLanguage {
+		w.Header().Add("Vary", "Accept-Language")
+	}
+}
+
+func parseAcceptLanguage(header string) string {
+	best := ""
+	bestQuality := 0.0
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if value, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		lang := tag
+		if i := strings.Index(tag, "-"); i > 0 {
+			lang = tag[:i]
+		}
+		if lang == "*" {
+			lang = defaultLang
+		}
+
+		if isSupportedLanguage(lang) && quality > bestQuality {
+			best = lang
+			bestQuality = quality
+		}
+	}
+
+	if best == "" {
+		return defaultLang
+	}
+	return best
+}
+
+func isSupportedLanguage(lang string) bool {
+	for _, supported := range supportedLanguages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveLanguage[V any](translations map[string]V, lang string) string {
+	if _, ok := translations[lang]; ok {
+		return lang
+	}
+	if _, ok := translations[defaultLang]; ok {
+		return defaultLang
+	}
+
+	if available := sortedLanguages(translations); len(available) > 0 {
+		return available[0]
+	}
+	return lang
+}
+
+func sortedLanguages[V any](translations map[string]V) []string {
+	languages := make([]string, 0, len(translations))
+	for lang := range translations {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+func localizedText(translations map[string]string, lang string) string {
+	return translations[resolveLanguage(translations, lang)]
+}
+
+// setContentLanguage announces the languages the localized items actually
+// use, which differ from the requested one when a translation is missing.
+// Full objects carry every supported language.
+func setContentLanguage(w http.ResponseWriter, r *http.Request, requested string, used []string) {
+	varyOnLanguage(w, r)
+	switch {
+	case len(used) > 0:
+		w.Header().Set("Content-Language", strings.Join(distinctLanguages(used), ", "))
+	case requested != "":
+		w.Header().Set("Content-Language", requested)
+	default:
+		w.Header().Set("Content-Language", strings.Join(supportedLanguages, ", "))
+	}
+}
+
+func distinctLanguages(languages []string) []string {
+	seen := make(map[string]bool)
+	var distinct []string
+	for _, lang := range languages {
+		if !seen[lang] {
+			seen[lang] = true
+			distinct = append(distinct, lang)
+		}
+	}
+	return distinct
+}
+
+func localizeQuestion(q models.Question, lang string, player bool) models.LocalizedQuestion {
+	content := q.I18n[resolveLanguage(q.I18n, lang)]
+
+	localized := models.LocalizedQuestion{
+		Kind:             q.Kind,
+		Version:          q.Version,
+		Slug:             q.Slug,
+		Theme:            q.Theme,
+		Subthemes:        q.Subthemes,
+		Tags:             q.Tags,
+		Qtype:            q.Qtype,
+		Difficulty:       q.Difficulty,
+		EstimatedSeconds: q.EstimatedSeconds,
+		Points:           q.Points,
+		ShuffleAnswers:   q.ShuffleAnswers,
+		Scoring:          q.Scoring,
+		TypoTolerance:    q.TypoTolerance,
+		Lang:             resolveLanguage(q.I18n, lang),
+		Title:            content.Title,
+		Stem:             content.Stem,
+		Media:            q.Media,
+	}
+	if player {
+		localized.FlagURL = questionFlagURL(q)
+	} else {
+		localized.Flag = q.Flag
+		localized.Explanation = content.Explanation
+		localized.Numeric = q.Numeric
+		localized.Sources = q.Sources
+	}
+	if q.Numeric != nil {
+		localized.Unit = q.Numeric.Unit
+	}
+
+	answers := q.Answers
+	if player {
+		answers = playerAnswers(q)
+	}
+	localized.Answers = make([]models.LocalizedAnswer, len(answers))
+	for i, a := range answers {
+		answer := models.LocalizedAnswer{
+			Slug:  a.Slug,
+			Label: a.I18n[resolveLanguage(a.I18n, lang)].Label,
+		}
+		if !player {
+			isCorrect := a.IsCorrect
+			answer.IsCorrect = &isCorrect
+			answer.Position = a.Position
+			answer.Accepted = a.Accepted[resolveLanguage(a.I18n, lang)]
+		}
+		localized.Answers[i] = answer
+	}
+
+	return localized
+}
+
+func localizeCountry(c models.Country, lang string) models.LocalizedCountry {
+	return models.LocalizedCountry{
+		Slug:         c.Slug,
+		ISOAlpha2:    c.ISOAlpha2,
+		ISOAlpha3:    c.ISOAlpha3,
+		ISONumerics:  c.ISONumerics,
+		Lang:         resolveLanguage(c.Name, lang),
+		Name:         localizedText(c.Name, lang),
+		OfficialName: localizedText(c.OfficialName, lang),
+		Capital:      localizedText(c.Capital, lang),
+		Continent:    c.Continent,
+		Region:       c.Region,
+		Coordinates:  c.Coordinates,
+		Flag:         c.Flag,
+		Population:   c.Population,
+		AreaKm2:      c.AreaKm2,
+		Currency:     c.Currency,
+		Languages:    c.Languages,
+		Neighbors:    c.Neighbors,
+		TLD:          c.TLD,
+		PhoneCode:    c.PhoneCode,
+		DrivingSide:  c.DrivingSide,
+		UNMember:     c.UNMember,
+	}
+}
+
+func localizeRegion(r models.Region, lang string) models.LocalizedRegion {
+	return models.LocalizedRegion{
+		Slug:      r.Slug,
+		Lang:      resolveLanguage(r.Name, lang),
+		Name:      localizedText(r.Name, lang),
+		Continent: r.Continent,
+		Countries: r.Countries,
+	}
+}
+
+func localizeContinent(c models.Continent, lang string) models.LocalizedContinent {
+	return models.LocalizedContinent{
+		Slug:       c.Slug,
+		Lang:       resolveLanguage(c.Name, lang),
+		Name:       localizedText(c.Name, lang),
+		Countries:  c.Countries,
+		AreaKm2:    c.AreaKm2,
+		Population: c.Population,
+	}
+}
+
+func presentCountries(countries []models.Country, lang string) (interface{}, []string) {
+	if lang == "" {
+		return countries, nil
+	}
+	localized := make([]models.LocalizedCountry, len(countries))
+	used := make([]string, len(countries))
+	for i, c := range countries {
+		localized[i] = localizeCountry(c, lang)
+		used[i] = localized[i].Lang
+	}
+	return localized, used
+}
+
+func presentRegions(regions []models.Region, lang string) (interface{}, []string) {
+	if lang == "" {
+		return regions, nil
+	}
+	localized := make([]models.LocalizedRegion, len(regions))
+	used := make([]string, len(regions))
+	for i, r := range regions {
+		localized[i] = localizeRegion(r, lang)
+		used[i] = localized[i].Lang
+	}
+	return localized, used
+}
+
+func presentContinents(continents []models.Continent, lang string) (interface{}, []string) {
+	if lang == "" {
+		return continents, nil
+	}
+	localized := make([]models.LocalizedContinent, len(continents))
+	used := make([]string, len(continents))
+	for i, c := range continents {
+		localized[i] = localizeContinent(c, lang)
+		used[i] = localized[i].Lang
+	}
+	return localized, used
+}
@@ -0,0 +1,175 @@
+package actions
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"cultpedia/internal/models"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"fr", "fr"},
+		{"fr-CA,fr;q=0.9,en;q=0.8", "fr"},
+		{"de-DE,de;q=0.9,es;q=0.5,en;q=0.4", "es"},
+		{"en;q=0.2, es;q=0.7", "es"},
+		{"de, it", "en"},
+		{"*", "en"},
+		{"ES", "es"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := parseAcceptLanguage(tt.header); got != tt.expected {
+				t.Errorf("parseAcceptLanguage(%q) = %q, expected %q", tt.header, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLocalizedQuestions(t *testing.T) {
+	setTestQuestions(t)
+
+	t.Run("query parameter", func(t *testing.T) {
+		rec := serveTestRequest(http.MethodGet, "/api/questions/history-q1?lang=es", "")
+		if rec.Header().Get("Content-Language") != "es" {
+			t.Errorf("Content-Language = %q, expected es", rec.Header().Get("Content-Language"))
+		}
+		var response struct {
+			Data models.LocalizedQuestion `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("invalid JSON response: %v", err)
+		}
+		if response.Data.Stem != "¿Pregunta?" || response.Data.Explanation != "Explicacion." || response.Data.Lang != "es" {
+			t.Errorf("unexpected localized question: %+v", response.Data)
+		}
+		if response.Data.Answers[0].IsCorrect == nil || !*response.Data.Answers[0].IsCorrect {
+			t.Error("full localized view should keep is_correct")
+		}
+	})
+
+	t.Run("accept-language keeps the full objects", func(t *testing.T) {
+		rec := serveWithHeaders("/api/questions?limit=1", map[string]string{"Accept-Language": "fr-FR,fr;q=0.9"})
+		if rec.Header().Get("Content-Language") != "en, fr, es" {
+			t.Errorf("Content-Language = %q, expected every language", rec.Header().Get("Content-Language"))
+		}
+		if !strings.Contains(rec.Body.String(), `"i18n"`) {
+			t.Errorf("expected all translations, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("lang=auto negotiates through accept-language", func(t *testing.T) {
+		rec := serveWithHeaders("/api/questions/history-q1?lang=auto", map[string]string{"Accept-Language": "de-DE,fr;q=0.9"})
+		if rec.Header().Get("Content-Language") != "fr" || rec.Header().Get("Vary") != "Accept-Language" {
+			t.Errorf("Content-Language = %q, Vary = %q", rec.Header().Get("Content-Language"), rec.Header().Get("Vary"))
+		}
+		if !strings.Contains(rec.Body.String(), `"lang":"fr"`) {
+			t.Errorf("expected the French question, got %s", rec.Body.String())
+		}
+
+		fallback := serveWithHeaders("/api/geography/countries?lang=auto", map[string]string{"Accept-Language": "de"})
+		if fallback.Header().Get("Content-Language") != "en" {
+			t.Errorf("Content-Language = %q, expected the en fallback", fallback.Header().Get("Content-Language"))
+		}
+		if plain := serveTestRequest(http.MethodGet, "/api/questions/history-q1?lang=fr", ""); plain.Header().Get("Vary") != "" {
+			t.Errorf("Vary = %q, expected none with an explicit lang", plain.Header().Get("Vary"))
+		}
+	})
+
+	t.Run("lang=all", func(t *testing.T) {
+		rec := serveTestRequest(http.MethodGet, "/api/questions/history-q1?lang=all", "")
+		if !strings.Contains(rec.Body.String(), `"i18n"`) {
+			t.Errorf("expected all translations, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("missing translation falls back to english", func(t *testing.T) {
+		q := createTestQuestion("partial", "art", "beginner", "single_choice")
+		delete(q.I18n, "es")
+		localized := localizeQuestion(q, "es", false)
+		if localized.Stem != "Question?" || localized.Lang != "en" {
+			t.Errorf("stem = %q in %q, expected the English fallback", localized.Stem, localized.Lang)
+		}
+	})
+
+	t.Run("content language of a fallback", func(t *testing.T) {
+		data := apiData.Load()
+		q := data.Questions[0]
+		delete(q.I18n, "es")
+		rec := serveTestRequest(http.MethodGet, "/api/questions/"+q.Slug+"?lang=es", "")
+		if got := rec.Header().Get("Content-Language"); got != "en" || !strings.Contains(rec.Body.String(), `"lang":"en"`) {
+			t.Errorf("Content-Language = %q, expected the English fallback in %s", got, rec.Body.String())
+		}
+	})
+
+	t.Run("player view", func(t *testing.T) {
+		rec := serveTestRequest(http.MethodGet, "/api/quiz?view=player&lang=fr&seed=3", "")
+		body := rec.Body.String()
+		if strings.Contains(body, "is_correct") || strings.Contains(body, "explanation") || strings.Contains(body, "sources") {
+			t.Errorf("localized player view leaks answers: %s", body)
+		}
+	})
+}
+
+func TestLocalizedGeography(t *testing.T) {
+	setTestQuestions(t)
+	data := apiData.Load()
+	data.Countries[0].Name = map[string]string{"en": "France", "fr": "France", "es": "Francia"}
+	data.Countries[0].Capital = map[string]string{"en": "Paris", "fr": "Paris", "es": "París"}
+	data.Regions[0].Name = map[string]string{"en": "Western Europe", "fr": "Europe de l'Ouest", "es": "Europa Occidental"}
+	data.Continents[0].Name = map[string]string{"en": "Europe", "fr": "Europe", "es": "Europa"}
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{"/api/geography/countries/fr?lang=es", `"name":"Francia"`},
+		{"/api/geography/countries?lang=es", `"capital":"París"`},
+		{"/api/geography/regions/western_europe?lang=fr", `"name":"Europe de l'Ouest"`},
+		{"/api/geography/regions?lang=es", `"name":"Europa Occidental"`},
+		{"/api/geography/continents/europe?lang=es", `"name":"Europa"`},
+		{"/api/geography/continents?lang=es", `"name":"Europa"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := serveTestRequest(http.MethodGet, tt.target, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, expected %d", rec.Code, http.StatusOK)
+			}
+			if !strings.Contains(rec.Body.String(), tt.expected) {
+				t.Errorf("expected %s in %s", tt.expected, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestETagVariesWithLanguage(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+	if err := reloadData(); err != nil {
+		t.Fatal(err)
+	}
+
+	english := serveTestRequest(http.MethodGet, "/api/questions?lang=en", "")
+	french := serveTestRequest(http.MethodGet, "/api/questions?lang=fr", "")
+	if english.Header().Get("ETag") == french.Header().Get("ETag") {
+		t.Error("ETag should differ between languages")
+	}
+
+	rec := serveWithHeaders("/api/questions?lang=fr", map[string]string{"If-None-Match": english.Header().Get("ETag")})
+	if rec.Code != http.StatusOK {
+		t.Errorf("English ETag should not validate a French response, got status %d", rec.Code)
+	}
+
+	full := serveTestRequest(http.MethodGet, "/api/questions", "")
+	withHeader := serveWithHeaders("/api/questions", map[string]string{"Accept-Language": "fr"})
+	if full.Header().Get("ETag") != withHeader.Header().Get("ETag") {
+		t.Error("Accept-Language should not change the ETag")
+	}
+}
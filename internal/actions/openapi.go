@@ -0,0 +1,255 @@
+package actions
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cultpedia/internal/models"
+)
+
+const openAPIVersion = "3.1.0"
+
+type jsonSchema map[string]interface{}
+
+var (
+	openAPIOnce sync.Once
+	openAPISpec map[string]interface{}
+)
+
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	openAPIOnce.Do(func() { openAPISpec = buildOpenAPISpec() })
+	writeJSON(w, http.StatusOK, openAPISpec)
+}
+
+func buildOpenAPISpec() map[string]interface{} {
+	gen := &schemaGenerator{components: map[string]jsonSchema{}}
+	errorSchema := gen.schemaFor(reflect.TypeOf(models.ErrorResponse{}))
+
+	paths := map[string]map[string]interface{}{}
+	for _, route := range apiRoutes() {
+		if paths[route.path] == nil {
+			paths[route.path] = map[string]interface{}{}
+		}
+		paths[route.path][strings.ToLower(route.method)] = gen.operation(route, errorSchema)
+	}
+
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":       "Cultpedia API",
+			"version":     "1.0",
+			"description": "API for Cultpedia questions and geography data",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": gen.components,
+			"securitySchemes": map[string]interface{}{
+				"adminToken": map[string]string{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+func (gen *schemaGenerator) operation(route apiRoute, errorSchema jsonSchema) map[string]interface{} {
+	op := map[string]interface{}{
+		"operationId": operationID(route),
+		"summary":     route.summary,
+	}
+
+	var params []map[string]interface{}
+	for _, name := range route.pathParams() {
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   jsonSchema{"type": "string"},
+		})
+	}
+	for _, p := range route.params {
+		schema := jsonSchema{"type": "string"}
+		if p.integer {
+			schema["type"] = "integer"
+		}
+		if len(p.enum) > 0 {
+			schema["enum"] = p.enum
+		}
+		params = append(params, map[string]interface{}{
+			"name":        p.name,
+			"in":          p.in,
+			"description": p.description,
+			"required":    p.required,
+			"schema":      schema,
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if route.requestBody != nil {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(gen.schemaFor(reflect.TypeOf(route.requestBody))),
+		}
+	}
+
+	status := route.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]interface{}{"description": http.StatusText(status)}
+	switch {
+	case route.contentType != "":
+		success["content"] = map[string]interface{}{
+			route.contentType: map[string]interface{}{"schema": jsonSchema{"type": "string"}},
+		}
+	case route.response != nil:
+		success["content"] = jsonContent(gen.responseSchema(route))
+	}
+
+	responses := map[string]interface{}{
+		strconv.Itoa(status): success,
+		"default": map[string]interface{}{
+			"description": "Error",
+			"content":     jsonContent(errorSchema),
+		},
+	}
+	if route.scope != "" {
+		responses[strconv.Itoa(http.StatusNotModified)] = map[string]interface{}{"description": http.StatusText(http.StatusNotModified)}
+	}
+	op["responses"] = responses
+
+	if route.auth {
+		op["security"] = []map[string][]string{{"adminToken": {}}}
+	}
+
+	return op
+}
+
+func (gen *schemaGenerator) responseSchema(route apiRoute) jsonSchema {
+	envelope := gen.schemaFor(reflect.TypeOf(route.response))
+	if len(route.data) == 0 {
+		return envelope
+	}
+
+	variants := make([]jsonSchema, len(route.data))
+	for i, v := range route.data {
+		variants[i] = gen.schemaFor(reflect.TypeOf(v))
+	}
+	data := variants[0]
+	if len(variants) > 1 {
+		data = jsonSchema{"oneOf": variants}
+	}
+	if route.list {
+		data = jsonSchema{"type": "array", "items": data}
+	}
+
+	return jsonSchema{
+		"allOf": []jsonSchema{
+			envelope,
+			{"type": "object", "properties": map[string]jsonSchema{"data": data}},
+		},
+	}
+}
+
+type schemaGenerator struct {
+	components map[string]jsonSchema
+}
+
+func (gen *schemaGenerator) schemaFor(t reflect.Type) jsonSchema {
+	if t.Kind() == reflect.Pointer {
+		return gen.schemaFor(t.Elem())
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return jsonSchema{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return gen.structSchema(t)
+		}
+		if _, ok := gen.components[t.Name()]; !ok {
+			gen.components[t.Name()] = nil
+			gen.components[t.Name()] = gen.structSchema(t)
+		}
+		return jsonSchema{"$ref": "#/components/schemas/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return jsonSchema{"type": "array", "items": gen.schemaFor(t.Elem())}
+	case reflect.Map:
+		return jsonSchema{"type": "object", "additionalProperties": gen.schemaFor(t.Elem())}
+	case reflect.String:
+		return jsonSchema{"type": "string"}
+	case reflect.Bool:
+		return jsonSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Uint, reflect.Uint32:
+		return jsonSchema{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return jsonSchema{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return jsonSchema{"type": "number"}
+	default:
+		return jsonSchema{}
+	}
+}
+
+func (gen *schemaGenerator) structSchema(t reflect.Type) jsonSchema {
+	properties := map[string]jsonSchema{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = gen.schemaFor(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+
+	schema := jsonSchema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func jsonContent(schema jsonSchema) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+func operationID(route apiRoute) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(route.method))
+	for _, segment := range strings.Split(route.path, "/") {
+		segment = strings.Trim(segment, "{}")
+		segment = strings.TrimSuffix(segment, ".json")
+		if segment == "" || segment == "api" {
+			continue
+		}
+		for _, part := range strings.FieldsFunc(segment, func(r rune) bool { return r == '_' || r == '-' }) {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	switch {
+	case route.path == "/":
+		b.WriteString("Index")
+	case b.Len() == len(route.method):
+		b.WriteString("Root")
+	}
+	return b.String()
+}
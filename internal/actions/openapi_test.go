@@ -0,0 +1,117 @@
+package actions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testOpenAPISpec struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]json.RawMessage `json:"schemas"`
+	} `json:"components"`
+}
+
+func fetchOpenAPISpec(t *testing.T) (testOpenAPISpec, string) {
+	t.Helper()
+	setTestQuestions(t)
+
+	rec := serveTestRequest(http.MethodGet, "/api/openapi.json", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, expected %d", rec.Code, http.StatusOK)
+	}
+	var spec testOpenAPISpec
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+	return spec, rec.Body.String()
+}
+
+// TestOpenAPICoversServedRoutes probes the mux the server runs with every
+// method on every documented path, and on a path below each of them, so a
+// route served outside the spec, or a documented route the mux does not
+// serve, fails the test.
+func TestOpenAPICoversServedRoutes(t *testing.T) {
+	spec, _ := fetchOpenAPISpec(t)
+	if len(spec.Paths) == 0 {
+		t.Fatal("the OpenAPI spec has no paths")
+	}
+	documented := make(map[string]bool)
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	mux := newAPIMux()
+	methods := []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+	values := strings.NewReplacer("{slug}", "some-slug", "{code}", "fr", "{file}", "image.png")
+	for path := range spec.Paths {
+		target := values.Replace(path)
+		for _, method := range methods {
+			_, pattern := mux.Handler(httptest.NewRequest(method, target, nil))
+			if documented[method+" "+path] && pattern != method+" "+path {
+				t.Errorf("%s %s is in the OpenAPI spec but the mux serves it with %q", method, path, pattern)
+			}
+			if pattern != "" && !documented[pattern] {
+				t.Errorf("%s %s is served by %q, which is missing from the OpenAPI spec", method, target, pattern)
+			}
+			below := strings.TrimSuffix(target, "/") + "/below"
+			_, pattern = mux.Handler(httptest.NewRequest(method, below, nil))
+			if pattern != "" && !documented[pattern] {
+				t.Errorf("%s %s is served by %q, which is missing from the OpenAPI spec", method, below, pattern)
+			}
+		}
+	}
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	spec, body := fetchOpenAPISpec(t)
+
+	if spec.OpenAPI != "3.1.0" {
+		t.Errorf("openapi = %q, expected 3.1.0", spec.OpenAPI)
+	}
+
+	for _, name := range []string{"Question", "PlayerQuestion", "LocalizedQuestion", "Country", "Region", "Continent", "PaginatedResponse", "ItemResponse", "ListResponse", "ErrorResponse"} {
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("schema %s is missing", name)
+		}
+	}
+
+	var question struct {
+		Properties map[string]map[string]interface{} `json:"properties"`
+		Required   []string                          `json:"required"`
+	}
+	if err := json.Unmarshal(spec.Components.Schemas["Question"], &question); err != nil {
+		t.Fatal(err)
+	}
+	if question.Properties["estimated_seconds"]["type"] != "integer" || question.Properties["answers"]["type"] != "array" {
+		t.Errorf("unexpected Question properties: %v", question.Properties)
+	}
+	for _, field := range question.Required {
+		if field == "sources" || field == "version" {
+			t.Errorf("omitempty field %s should not be required", field)
+		}
+	}
+
+	for _, ref := range strings.Split(body, `"$ref":"#/components/schemas/`)[1:] {
+		name := ref[:strings.Index(ref, `"`)]
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("reference to undefined schema %s", name)
+		}
+	}
+}
+
+func TestRootEndpointsMatchRoutes(t *testing.T) {
+	spec, _ := fetchOpenAPISpec(t)
+
+	for _, endpoint := range apiEndpoints() {
+		if _, ok := spec.Paths[endpoint.Path][strings.ToLower(endpoint.Method)]; !ok {
+			t.Errorf("endpoint %s %s is listed but missing from the OpenAPI spec", endpoint.Method, endpoint.Path)
+		}
+	}
+}
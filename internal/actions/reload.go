@@ -0,0 +1,183 @@
+package actions
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cultpedia/internal/checks"
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+const (
+	defaultReloadInterval = 30 * time.Second
+
+	reloadIntervalEnv = "CULTPEDIA_RELOAD_INTERVAL"
+	adminTokenEnv     = "CULTPEDIA_ADMIN_TOKEN"
+)
+
+var reloadMu sync.Mutex
+
+var watchedDatasetFiles = []string{
+	utils.ManifestFile,
+	utils.QuestionsFile,
+	utils.GeographyManifestFile,
+	utils.CountriesFile,
+	utils.ContinentsFile,
+	utils.RegionsFile,
+}
+
+func reloadData() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	data, err := loadData()
+	if err != nil {
+		return err
+	}
+
+	if err := validateSnapshot(data); err != nil {
+		return fmt.Errorf("dataset validation failed:\n%w", err)
+	}
+
+	apiData.Store(data)
+	return nil
+}
+
+// loadInitialData loads the datasets at startup. When they fail to load or to
+// validate, the server still starts but answers 503 until a reload succeeds,
+// either from the file watcher or from the admin endpoint.
+func loadInitialData() {
+	if err := reloadData(); err != nil {
+		log.Printf("Datasets could not be loaded, answering 503 until a reload succeeds:\n%v", err)
+	}
+}
+
+// requireData answers 503 while no valid snapshot has been loaded.
+func requireData(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if apiData.Load() == nil {
+			writeError(w, http.StatusServiceUnavailable, "datasets are not loaded yet")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func validateSnapshot(data *models.APIData) error {
+	var errors []string
+
+	if err := checks.ValidateQuestionSet(data.Questions); err != nil {
+		errors = append(errors, fmt.Sprintf("Questions validation failed:\n%v", err))
+	}
+
+	if err := checks.ValidateGeographySet(data.Countries, data.Continents, data.Regions); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "\n\n"))
+	}
+
+	return nil
+}
+
+func reloadInterval() time.Duration {
+	raw := os.Getenv(reloadIntervalEnv)
+	if raw == "" {
+		return defaultReloadInterval
+	}
+
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval < 0 {
+		log.Printf("Invalid %s '%s', using %s", reloadIntervalEnv, raw, defaultReloadInterval)
+		return defaultReloadInterval
+	}
+	return interval
+}
+
+func watchDatasets(interval time.Duration) {
+	watcher := &datasetWatcher{last: datasetFingerprint()}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		watcher.poll()
+	}
+}
+
+// datasetWatcher reloads the datasets when their fingerprint changes. The
+// fingerprint is only recorded once a reload succeeds, so a failed reload is
+// retried on the next tick; failed remembers it to log each failure once.
+type datasetWatcher struct {
+	last   string
+	failed string
+}
+
+func (w *datasetWatcher) poll() {
+	current := datasetFingerprint()
+	if current == w.last {
+		return
+	}
+
+	if err := reloadData(); err != nil {
+		if current != w.failed {
+			log.Printf("Dataset change detected but reload failed, keeping the previous data: %v", err)
+			w.failed = current
+		}
+		return
+	}
+	w.last = current
+	w.failed = ""
+	log.Printf("Datasets reloaded after file change")
+}
+
+func datasetFingerprint() string {
+	var parts []string
+	for _, path := range watchedDatasetFiles {
+		info, err := os.Stat(path)
+		if err != nil {
+			parts = append(parts, path+":missing")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", path, info.Size(), info.ModTime().UnixNano()))
+	}
+	return strings.Join(parts, "|")
+}
+
+func handleReload(w http.ResponseWriter, r *http.Request) {
+	token := os.Getenv(adminTokenEnv)
+	if token == "" {
+		writeError(w, http.StatusForbidden, "admin endpoints are disabled (set "+adminTokenEnv+" to enable them)")
+		return
+	}
+
+	provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+		writeError(w, http.StatusUnauthorized, "invalid admin token")
+		return
+	}
+
+	if err := reloadData(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	data := apiData.Load()
+	writeJSON(w, http.StatusOK, models.ReloadResponse{
+		Status:    "reloaded",
+		Manifests: data.Manifests,
+		Stats: map[string]int{
+			"questions":  len(data.Questions),
+			"countries":  len(data.Countries),
+			"regions":    len(data.Regions),
+			"continents": len(data.Continents),
+		},
+	})
+}
@@ -0,0 +1,232 @@
+package actions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+func createValidDatasetQuestion(slug string) models.Question {
+	q := createTestQuestion(slug, "history", "beginner", "single_choice")
+	q.EstimatedSeconds = 15
+	q.Sources = []string{"https://example.com"}
+	return q
+}
+
+func writeNDJSON(t *testing.T, path string, items ...interface{}) {
+	t.Helper()
+	var lines []string
+	for _, item := range items {
+		line, err := json.Marshal(item)
+		if err != nil {
+			t.Fatalf("marshal error: %v", err)
+		}
+		lines = append(lines, string(line))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setupTestDatasets(t *testing.T, questions ...models.Question) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	names := map[string]string{"en": "France", "fr": "France", "es": "Francia"}
+	writeNDJSON(t, utils.ManifestFile, map[string]string{"version": "1.0.0", "updated_at": "2025-01-01T00:00:00Z"})
+	writeNDJSON(t, utils.GeographyManifestFile, map[string]string{"version": "1.0.0", "updated_at": "2025-01-01T00:00:00Z"})
+	writeNDJSON(t, utils.CountriesFile, models.Country{
+		Slug: "fr", ISOAlpha2: "FR", ISOAlpha3: "FRA", ISONumerics: "250",
+		Name: names, OfficialName: names, Capital: names,
+		Continent: "europe", Region: "western_europe", DrivingSide: "right",
+		Coordinates: models.Coordinates{Lat: 46, Lng: 2}, PhoneCode: "+33",
+	})
+	writeNDJSON(t, utils.ContinentsFile, models.Continent{Slug: "europe", Name: names, Countries: []string{"fr"}})
+	writeNDJSON(t, utils.RegionsFile, models.Region{Slug: "western_europe", Name: names, Continent: "europe", Countries: []string{"fr"}})
+
+	items := make([]interface{}, len(questions))
+	for i, q := range questions {
+		items[i] = q
+	}
+	writeNDJSON(t, utils.QuestionsFile, items...)
+
+	previous := apiData.Load()
+	t.Cleanup(func() { apiData.Store(previous) })
+}
+
+func TestReloadData(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+
+	if err := reloadData(); err != nil {
+		t.Fatalf("reloadData() returned unexpected error: %v", err)
+	}
+	first := apiData.Load()
+	if len(first.Questions) != 1 || first.QuestionIndex["history-first"] != 0 {
+		t.Fatalf("unexpected snapshot after first load: %d questions", len(first.Questions))
+	}
+
+	t.Run("valid update is swapped in", func(t *testing.T) {
+		writeNDJSON(t, utils.QuestionsFile, createValidDatasetQuestion("history-first"), createValidDatasetQuestion("history-second"))
+		if err := reloadData(); err != nil {
+			t.Fatalf("reloadData() returned unexpected error: %v", err)
+		}
+		if len(apiData.Load().Questions) != 2 {
+			t.Errorf("expected 2 questions after reload, got %d", len(apiData.Load().Questions))
+		}
+	})
+
+	t.Run("invalid update keeps previous snapshot", func(t *testing.T) {
+		before := apiData.Load()
+		invalid := createValidDatasetQuestion("history-third")
+		invalid.Answers = invalid.Answers[:2]
+		writeNDJSON(t, utils.QuestionsFile, createValidDatasetQuestion("history-first"), invalid)
+
+		if err := reloadData(); err == nil {
+			t.Fatal("reloadData() should fail on invalid data")
+		}
+		if apiData.Load() != before {
+			t.Error("previous snapshot should be kept when validation fails")
+		}
+	})
+
+	t.Run("unreadable update keeps previous snapshot", func(t *testing.T) {
+		before := apiData.Load()
+		if err := os.WriteFile(utils.QuestionsFile, []byte("{not json"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := reloadData(); err == nil {
+			t.Fatal("reloadData() should fail on malformed NDJSON")
+		}
+		if apiData.Load() != before {
+			t.Error("previous snapshot should be kept when loading fails")
+		}
+	})
+}
+
+func TestLoadInitialDataRejectsInvalidData(t *testing.T) {
+	invalid := createValidDatasetQuestion("history-first")
+	invalid.Answers = invalid.Answers[:2]
+	setupTestDatasets(t, invalid)
+	apiData.Store(nil)
+
+	loadInitialData()
+	if apiData.Load() != nil {
+		t.Fatal("startup should not serve data that fails validation")
+	}
+	for _, target := range []string{"/api/", "/api/questions", "/api/geography/countries"} {
+		if rec := serveTestRequest(http.MethodGet, target, ""); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: status = %d, expected %d", target, rec.Code, http.StatusServiceUnavailable)
+		}
+	}
+
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+	t.Setenv(adminTokenEnv, "secret")
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/reload", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	newAPIMux().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reload status = %d, expected %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if rec := serveTestRequest(http.MethodGet, "/api/questions", ""); rec.Code != http.StatusOK {
+		t.Errorf("status after a valid reload = %d, expected %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestDatasetWatcherRetriesFailedReload(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+	if err := reloadData(); err != nil {
+		t.Fatal(err)
+	}
+	watcher := &datasetWatcher{last: datasetFingerprint()}
+
+	if err := os.WriteFile(utils.QuestionsFile, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	watcher.poll()
+	if watcher.last == datasetFingerprint() {
+		t.Fatal("a failed reload should not record the fingerprint")
+	}
+
+	writeNDJSON(t, utils.QuestionsFile, createValidDatasetQuestion("history-first"), createValidDatasetQuestion("history-second"))
+	watcher.poll()
+	if len(apiData.Load().Questions) != 2 || watcher.last != datasetFingerprint() {
+		t.Errorf("the watcher should reload once the files are fixed, got %d questions", len(apiData.Load().Questions))
+	}
+}
+
+func TestDatasetFingerprint(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+
+	before := datasetFingerprint()
+	writeNDJSON(t, utils.QuestionsFile, createValidDatasetQuestion("history-first"), createValidDatasetQuestion("history-second"))
+	if datasetFingerprint() == before {
+		t.Error("fingerprint should change when a dataset file changes")
+	}
+}
+
+func TestHandleReload(t *testing.T) {
+	setupTestDatasets(t, createValidDatasetQuestion("history-first"))
+	if err := reloadData(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("disabled without token", func(t *testing.T) {
+		t.Setenv(adminTokenEnv, "")
+		rec := serveTestRequest(http.MethodPost, "/api/admin/reload", "")
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("status = %d, expected %d", rec.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Setenv(adminTokenEnv, "secret")
+
+	t.Run("wrong token", func(t *testing.T) {
+		rec := serveTestRequest(http.MethodPost, "/api/admin/reload", "")
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, expected %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		writeNDJSON(t, utils.QuestionsFile, createValidDatasetQuestion("history-first"), createValidDatasetQuestion("history-second"))
+		rec := httptestReload("Bearer secret")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		if len(apiData.Load().Questions) != 2 {
+			t.Errorf("expected 2 questions after reload, got %d", len(apiData.Load().Questions))
+		}
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		invalid := createValidDatasetQuestion("history-third")
+		invalid.Kind = "nope"
+		writeNDJSON(t, utils.QuestionsFile, invalid)
+		rec := httptestReload("Bearer secret")
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("status = %d, expected %d", rec.Code, http.StatusUnprocessableEntity)
+		}
+		if len(apiData.Load().Questions) != 2 {
+			t.Error("previous snapshot should still be served")
+		}
+	})
+}
+
+func httptestReload(authorization string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/reload", nil)
+	req.Header.Set("Authorization", authorization)
+	newAPIMux().ServeHTTP(rec, req)
+	return rec
+}
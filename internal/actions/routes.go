@@ -0,0 +1,324 @@
+package actions
+
+import (
+	"net/http"
+	"strings"
+
+	"cultpedia/internal/models"
+)
+
+type apiRoute struct {
+	method      string
+	path        string
+	summary     string
+	handler     http.HandlerFunc
+	scope       string
+	params      []apiParam
+	requestBody interface{}
+	response    interface{}
+	data        []interface{}
+	list        bool
+	contentType string
+	status      int
+	auth        bool
+	unlisted    bool
+}
+
+type apiParam struct {
+	name        string
+	in          string
+	description string
+	enum        []string
+	integer     bool
+	required    bool
+}
+
+type routeRegistrar interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+var (
+	langParams = []apiParam{
+		{name: "lang", in: "query", description: "Return localized objects in this language, in the best Accept-Language match for 'auto', or 'all' (the default) for every translation", enum: []string{"en", "fr", "es", negotiateLanguage, allLanguages}},
+		{name: "Accept-Language", in: "header", description: "Preferred languages, used with lang=auto"},
+	}
+
+	answerLangParams = []apiParam{
+		{name: "lang", in: "query", description: "Language of the submitted answer when the body does not set it", enum: []string{"en", "fr", "es", negotiateLanguage}},
+		{name: "Accept-Language", in: "header", description: "Preferred languages, used when neither the body nor lang sets one"},
+	}
+
+	viewParam = apiParam{name: "view", in: "query", description: "'player' hides answers, explanations and sources", enum: []string{viewFull, viewPlayer}}
+
+	questionFilterParams = []apiParam{
+		{name: "theme", in: "query", description: "Theme slug"},
+		{name: "subtheme", in: "query", description: "Subtheme slug"},
+		{name: "tag", in: "query", description: "Tag slug"},
+		{name: "difficulty", in: "query", description: "Difficulty level", enum: []string{"beginner", "intermediate", "advanced", "pro"}},
+		{name: "qtype", in: "query", description: "Question type", enum: []string{"single_choice", "multiple_choice", "true_false", "ordering", "numeric", "text_input"}},
+	}
+
+	paginationParams = []apiParam{
+		{name: "limit", in: "query", description: "Page size, between 1 and 500", integer: true},
+		{name: "offset", in: "query", description: "Number of matching questions to skip", integer: true},
+	}
+
+	quizParams = []apiParam{
+		{name: "count", in: "query", description: "Number of questions, between 1 and 50", integer: true},
+		{name: "seed", in: "query", description: "Seed for a reproducible quiz", integer: true},
+	}
+
+	geoQuizParams = []apiParam{
+		{name: "kind", in: "query", description: "Comma-separated question kinds: capital, flag, population, neighbor, population_estimate, area"},
+	}
+
+	searchParams = []apiParam{
+		{name: "q", in: "query", description: "Search terms, accent and case insensitive", required: true},
+		{name: "type", in: "query", description: "Only search questions or countries", enum: []string{searchTypeQuestion, searchTypeCountry}},
+		{name: "limit", in: "query", description: "Page size, between 1 and 500", integer: true},
+		{name: "offset", in: "query", description: "Number of results to skip", integer: true},
+	}
+
+	questionVariants  = []interface{}{models.Question{}, models.PlayerQuestion{}, models.LocalizedQuestion{}}
+	countryVariants   = []interface{}{models.Country{}, models.LocalizedCountry{}}
+	regionVariants    = []interface{}{models.Region{}, models.LocalizedRegion{}}
+	continentVariants = []interface{}{models.Continent{}, models.LocalizedContinent{}}
+)
+
+func apiRoutes() []apiRoute {
+	return []apiRoute{
+		{
+			method:   http.MethodGet,
+			path:     "/api/questions",
+			summary:  "List questions (filters: theme, subtheme, tag, difficulty, qtype, lang; pagination: limit, offset; view=player hides answers)",
+			handler:  handleQuestions,
+			scope:    scopeGeneralKnowledge,
+			params:   joinParams(questionFilterParams, paginationParams, []apiParam{viewParam}, langParams),
+			response: models.PaginatedResponse{},
+			data:     questionVariants,
+			list:     true,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/questions/{slug}",
+			summary:  "Get a question by slug",
+			handler:  handleQuestion,
+			scope:    scopeGeneralKnowledge,
+			params:   joinParams([]apiParam{viewParam}, langParams),
+			response: models.ItemResponse{},
+			data:     questionVariants,
+		},
+		{
+			method:      http.MethodPost,
+			path:        "/api/questions/{slug}/answer",
+			summary:     "Check an answer and get the correct answer, explanation and points earned",
+			handler:     handleAnswer,
+			params:      answerLangParams,
+			requestBody: models.AnswerSubmission{},
+			response:    models.AnswerResult{},
+		},
+		{
+			method:   http.MethodOptions,
+			path:     "/api/questions/{slug}/answer",
+			summary:  "CORS preflight for answer submissions",
+			handler:  handlePreflight,
+			status:   http.StatusNoContent,
+			unlisted: true,
+		},
+		{
+			method:      http.MethodGet,
+			path:        "/api/questions/{slug}/flag",
+			summary:     "Get the flag SVG of a question (its flag_url in the player view), without the country code",
+			handler:     handleQuestionFlag,
+			contentType: "image/svg+xml",
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/quiz",
+			summary:  "Generate a random quiz (count, seed, theme, difficulty, qtype, view)",
+			handler:  handleQuiz,
+			params:   joinParams(quizParams, questionFilterParams, []apiParam{viewParam}, langParams),
+			response: models.QuizResponse{},
+			data:     questionVariants,
+			list:     true,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/search",
+			summary:  "Search question texts and answers, country names and capitals in all languages (q, type, limit, offset)",
+			handler:  handleSearch,
+			scope:    scopeAll,
+			params:   searchParams,
+			response: models.PaginatedResponse{},
+			data:     []interface{}{models.SearchResult{}},
+			list:     true,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/geography/countries",
+			summary:  "Get all countries",
+			handler:  handleCountries,
+			scope:    scopeGeography,
+			params:   langParams,
+			response: models.ListResponse{},
+			data:     countryVariants,
+			list:     true,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/geography/countries/{code}",
+			summary:  "Get a country by slug, ISO alpha-2, alpha-3 or numeric code",
+			handler:  handleCountry,
+			scope:    scopeGeography,
+			params:   langParams,
+			response: models.ItemResponse{},
+			data:     countryVariants,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/geography/regions",
+			summary:  "Get all regions",
+			handler:  handleRegions,
+			scope:    scopeGeography,
+			params:   langParams,
+			response: models.ListResponse{},
+			data:     regionVariants,
+			list:     true,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/geography/regions/{slug}",
+			summary:  "Get a region by slug",
+			handler:  handleRegion,
+			scope:    scopeGeography,
+			params:   langParams,
+			response: models.ItemResponse{},
+			data:     regionVariants,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/geography/continents",
+			summary:  "Get all continents",
+			handler:  handleContinents,
+			scope:    scopeGeography,
+			params:   langParams,
+			response: models.ListResponse{},
+			data:     continentVariants,
+			list:     true,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/geography/continents/{slug}",
+			summary:  "Get a continent by slug",
+			handler:  handleContinent,
+			scope:    scopeGeography,
+			params:   langParams,
+			response: models.ItemResponse{},
+			data:     continentVariants,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/geography/quiz",
+			summary:  "Generate a quiz from the geography dataset (count, seed, kind, view); answers go to /api/questions/{slug}/answer",
+			handler:  handleGeographyQuiz,
+			params:   joinParams(quizParams, geoQuizParams, []apiParam{viewParam}, langParams),
+			response: models.QuizResponse{},
+			data:     questionVariants,
+			list:     true,
+		},
+		{
+			method:      http.MethodGet,
+			path:        "/api/geography/flags/{code}",
+			summary:     "Get country flag SVG (use ISO Alpha2 code)",
+			handler:     handleFlags,
+			contentType: "image/svg+xml",
+		},
+		{
+			method:      http.MethodGet,
+			path:        "/api/media/{file}",
+			summary:     "Get a question image or audio file (the file of its media field)",
+			handler:     handleMedia,
+			contentType: "application/octet-stream",
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/api/admin/reload",
+			summary:  "Reload and validate the datasets from disk",
+			handler:  handleReload,
+			response: models.ReloadResponse{},
+			auth:     true,
+			unlisted: true,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/openapi.json",
+			summary:  "OpenAPI 3.1 description of this API",
+			handler:  handleOpenAPI,
+			response: map[string]interface{}{},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/api/",
+			summary:  "API information, dataset versions and endpoints",
+			handler:  handleRoot,
+			scope:    scopeAll,
+			response: models.APIRootResponse{},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/",
+			summary:  "API information, dataset versions and endpoints",
+			handler:  handleRoot,
+			scope:    scopeAll,
+			response: models.APIRootResponse{},
+			unlisted: true,
+		},
+	}
+}
+
+func registerRoutes(reg routeRegistrar) {
+	for _, route := range apiRoutes() {
+		handler := route.handler
+		if route.scope != "" {
+			handler = cached(route.scope, handler)
+		}
+		// Admin routes run without data, so a reload can bring the server up.
+		if !route.auth {
+			handler = requireData(handler)
+		}
+		reg.HandleFunc(route.method+" "+route.path, handler)
+	}
+}
+
+func apiEndpoints() []models.EndpointInfo {
+	var endpoints []models.EndpointInfo
+	for _, route := range apiRoutes() {
+		if route.unlisted {
+			continue
+		}
+		endpoints = append(endpoints, models.EndpointInfo{
+			Path:        route.path,
+			Method:      route.method,
+			Description: route.summary,
+		})
+	}
+	return endpoints
+}
+
+func (route apiRoute) pathParams() []string {
+	var params []string
+	for _, segment := range strings.Split(route.path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params = append(params, strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}"))
+		}
+	}
+	return params
+}
+
+func joinParams(groups ...[]apiParam) []apiParam {
+	var params []apiParam
+	for _, group := range groups {
+		params = append(params, group...)
+	}
+	return params
+}
@@ -0,0 +1,235 @@
+package actions
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"cultpedia/internal/models"
+	"cultpedia/pkg/textmatch"
+)
+
+func checkAnswer(q models.Question, submission models.AnswerSubmission) (models.AnswerResult, error) {
+	switch q.Qtype {
+	case "numeric":
+		return checkNumeric(q, submission)
+	case "text_input":
+		return checkTextInput(q, submission)
+	}
+
+	picks := submission.Answers
+	if len(picks) == 0 && submission.Answer != "" {
+		picks = []string{submission.Answer}
+	}
+	if len(picks) == 0 {
+		return models.AnswerResult{}, fmt.Errorf("answer is required")
+	}
+	if submission.Value != nil {
+		return models.AnswerResult{}, fmt.Errorf("question '%s' does not take a numeric value", q.Slug)
+	}
+	if len(picks) > 1 && q.Qtype != "multiple_choice" && q.Qtype != "ordering" {
+		return models.AnswerResult{}, fmt.Errorf("question '%s' takes a single answer", q.Slug)
+	}
+
+	lang, err := answerLanguage(q, submission)
+	if err != nil {
+		return models.AnswerResult{}, err
+	}
+
+	options := make(map[string]models.Answer, len(q.Answers))
+	for _, a := range q.Answers {
+		options[a.Slug] = a
+	}
+	picked := make(map[string]bool, len(picks))
+	hits, misses := 0, 0
+	for _, slug := range picks {
+		a, ok := options[slug]
+		if !ok {
+			return models.AnswerResult{}, fmt.Errorf("answer '%s' is not an option of question '%s'", slug, q.Slug)
+		}
+		if picked[slug] {
+			return models.AnswerResult{}, fmt.Errorf("answer '%s' is given more than once", slug)
+		}
+		picked[slug] = true
+		if a.IsCorrect {
+			hits++
+		} else {
+			misses++
+		}
+	}
+
+	if q.Qtype == "ordering" {
+		return checkOrdering(q, picks, lang)
+	}
+
+	var correct []models.CorrectAnswer
+	for _, a := range q.Answers {
+		if a.IsCorrect {
+			correct = append(correct, models.CorrectAnswer{Slug: a.Slug, Label: a.I18n[lang].Label})
+		}
+	}
+
+	result := models.AnswerResult{
+		Question:    q.Slug,
+		Correct:     hits == len(correct) && misses == 0,
+		Explanation: q.I18n[lang].Explanation,
+		Lang:        lang,
+		MaxPoints:   q.Points,
+	}
+	if q.Qtype == "multiple_choice" {
+		result.Answers = picks
+		result.CorrectAnswers = correct
+	} else {
+		result.Answer = picks[0]
+		if len(correct) > 0 {
+			result.CorrectAnswer = &correct[0]
+		}
+	}
+
+	switch {
+	case result.Correct:
+		result.Points = q.Points
+	case q.Scoring == "partial" && len(correct) > 0:
+		// Each wrong pick cancels a right one, so selecting every option
+		// does not earn anything.
+		share := math.Max(0, float64(hits-misses)/float64(len(correct)))
+		result.Points = math.Round(q.Points*share*100) / 100
+	}
+	return result, nil
+}
+
+// checkOrdering scores the submitted order of every answer. Partial scoring
+// counts the pairs of answers placed in the right order relative to each
+// other, so one event out of place only loses the pairs it belongs to.
+func checkOrdering(q models.Question, picks []string, lang string) (models.AnswerResult, error) {
+	if len(picks) != len(q.Answers) {
+		return models.AnswerResult{}, fmt.Errorf("ordering question '%s' needs all %d answers in order (got %d)", q.Slug, len(q.Answers), len(picks))
+	}
+
+	positions := make(map[string]int, len(q.Answers))
+	ordered := make([]models.Answer, len(q.Answers))
+	copy(ordered, q.Answers)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Position < ordered[j].Position })
+
+	result := models.AnswerResult{
+		Question:    q.Slug,
+		Answers:     picks,
+		Explanation: q.I18n[lang].Explanation,
+		Lang:        lang,
+		MaxPoints:   q.Points,
+	}
+	for _, a := range ordered {
+		positions[a.Slug] = a.Position
+		result.CorrectAnswers = append(result.CorrectAnswers, models.CorrectAnswer{Slug: a.Slug, Label: a.I18n[lang].Label})
+	}
+
+	pairs, inOrder := 0, 0
+	for i := range picks {
+		for j := i + 1; j < len(picks); j++ {
+			pairs++
+			if positions[picks[i]] < positions[picks[j]] {
+				inOrder++
+			}
+		}
+	}
+
+	result.Correct = inOrder == pairs
+	switch {
+	case result.Correct:
+		result.Points = q.Points
+	case q.Scoring == "partial":
+		result.Points = math.Round(q.Points*float64(inOrder)/float64(pairs)*100) / 100
+	}
+	return result, nil
+}
+
+// checkNumeric accepts any value within the tolerance. Partial scoring then
+// decreases the points linearly, down to 0 at twice the tolerance.
+func checkNumeric(q models.Question, submission models.AnswerSubmission) (models.AnswerResult, error) {
+	if submission.Value == nil {
+		return models.AnswerResult{}, fmt.Errorf("value is required")
+	}
+	if submission.Answer != "" || len(submission.Answers) > 0 {
+		return models.AnswerResult{}, fmt.Errorf("question '%s' takes a numeric value, not answer slugs", q.Slug)
+	}
+	if q.Numeric == nil {
+		return models.AnswerResult{}, fmt.Errorf("question '%s' has no numeric answer", q.Slug)
+	}
+	lang, err := answerLanguage(q, submission)
+	if err != nil {
+		return models.AnswerResult{}, err
+	}
+
+	expected := *q.Numeric
+	tolerance := expected.Tolerance
+	if expected.RelativeTolerance > 0 {
+		tolerance = expected.RelativeTolerance * math.Abs(expected.Value)
+	}
+	distance := math.Abs(*submission.Value - expected.Value)
+
+	result := models.AnswerResult{
+		Question:     q.Slug,
+		Value:        submission.Value,
+		Correct:      distance <= tolerance,
+		CorrectValue: &expected,
+		Explanation:  q.I18n[lang].Explanation,
+		Lang:         lang,
+		MaxPoints:    q.Points,
+	}
+	switch {
+	case result.Correct:
+		result.Points = q.Points
+	case q.Scoring == "partial" && tolerance > 0:
+		share := math.Max(0, 1-(distance-tolerance)/tolerance)
+		result.Points = math.Round(q.Points*share*100) / 100
+	}
+	return result, nil
+}
+
+// checkTextInput matches the submitted text against the label and accepted
+// variants of every answer, in the submission language.
+func checkTextInput(q models.Question, submission models.AnswerSubmission) (models.AnswerResult, error) {
+	if strings.TrimSpace(submission.Answer) == "" {
+		return models.AnswerResult{}, fmt.Errorf("answer is required")
+	}
+	if len(submission.Answers) > 0 || submission.Value != nil {
+		return models.AnswerResult{}, fmt.Errorf("question '%s' takes a single text answer", q.Slug)
+	}
+	lang, err := answerLanguage(q, submission)
+	if err != nil {
+		return models.AnswerResult{}, err
+	}
+
+	result := models.AnswerResult{
+		Question:    q.Slug,
+		Answer:      submission.Answer,
+		Explanation: q.I18n[lang].Explanation,
+		Lang:        lang,
+		MaxPoints:   q.Points,
+	}
+	for i, a := range q.Answers {
+		label := a.I18n[lang].Label
+		variants := append([]string{label}, a.Accepted[lang]...)
+		if _, ok := textmatch.Match(submission.Answer, variants, lang, q.TypoTolerance); ok || i == 0 {
+			result.CorrectAnswer = &models.CorrectAnswer{Slug: a.Slug, Label: label}
+			if ok {
+				result.Correct = true
+				result.Points = q.Points
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func answerLanguage(q models.Question, submission models.AnswerSubmission) (string, error) {
+	lang := submission.Lang
+	if lang == "" {
+		lang = defaultLang
+	}
+	if _, ok := q.I18n[lang]; !ok {
+		return "", fmt.Errorf("language '%s' is not available for question '%s'", lang, q.Slug)
+	}
+	return lang, nil
+}
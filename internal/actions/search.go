@@ -0,0 +1,232 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+const (
+	searchTypeQuestion = "question"
+	searchTypeCountry  = "country"
+
+	minPrefixLength = 3
+	prefixMatchRate = 0.5
+)
+
+var searchFieldWeights = map[string]float64{
+	"title":         3,
+	"name":          3,
+	"official_name": 2,
+	"capital":       2,
+	"stem":          1.5,
+	"answer":        1.5,
+	"explanation":   1,
+}
+
+func buildSearchIndex(data *models.APIData) *models.SearchIndex {
+	index := &models.SearchIndex{Postings: make(map[string][]int)}
+
+	add := func(typ, slug, field, lang, text string) {
+		tokens := utils.Tokenize(text)
+		if len(tokens) == 0 {
+			return
+		}
+		id := len(index.Fields)
+		index.Fields = append(index.Fields, models.SearchField{Type: typ, Slug: slug, Field: field, Lang: lang, Text: text})
+		seen := make(map[string]bool, len(tokens))
+		for _, token := range tokens {
+			if !seen[token] {
+				seen[token] = true
+				index.Postings[token] = append(index.Postings[token], id)
+			}
+		}
+	}
+
+	for _, q := range data.Questions {
+		for _, lang := range sortedLanguages(q.I18n) {
+			add(searchTypeQuestion, q.Slug, "title", lang, q.I18n[lang].Title)
+			add(searchTypeQuestion, q.Slug, "stem", lang, q.I18n[lang].Stem)
+			add(searchTypeQuestion, q.Slug, "explanation", lang, q.I18n[lang].Explanation)
+		}
+		for _, a := range q.Answers {
+			for _, lang := range sortedLanguages(a.I18n) {
+				add(searchTypeQuestion, q.Slug, "answer", lang, a.I18n[lang].Label)
+			}
+		}
+	}
+
+	for _, c := range data.Countries {
+		for _, lang := range sortedLanguages(c.Name) {
+			add(searchTypeCountry, c.Slug, "name", lang, c.Name[lang])
+		}
+		for _, lang := range sortedLanguages(c.OfficialName) {
+			add(searchTypeCountry, c.Slug, "official_name", lang, c.OfficialName[lang])
+		}
+		for _, lang := range sortedLanguages(c.Capital) {
+			add(searchTypeCountry, c.Slug, "capital", lang, c.Capital[lang])
+		}
+	}
+
+	index.Tokens = make([]string, 0, len(index.Postings))
+	for token := range index.Postings {
+		index.Tokens = append(index.Tokens, token)
+	}
+	sort.Strings(index.Tokens)
+
+	return index
+}
+
+func search(index *models.SearchIndex, query, typ string) []models.SearchResult {
+	if index == nil {
+		return nil
+	}
+
+	terms := uniqueStrings(utils.Tokenize(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	type documentMatch struct {
+		score   float64
+		matched int
+		best    int
+	}
+	documents := make(map[string]*documentMatch)
+	fieldScores := make(map[int]float64)
+
+	for _, term := range terms {
+		termScores := make(map[int]float64)
+		for _, id := range index.Postings[term] {
+			termScores[id] = 1
+		}
+		if len([]rune(term)) >= minPrefixLength {
+			for i := sort.SearchStrings(index.Tokens, term); i < len(index.Tokens) && strings.HasPrefix(index.Tokens[i], term); i++ {
+				for _, id := range index.Postings[index.Tokens[i]] {
+					if termScores[id] == 0 {
+						termScores[id] = prefixMatchRate
+					}
+				}
+			}
+		}
+
+		documentScores := make(map[string]float64)
+		for id, rate := range termScores {
+			field := index.Fields[id]
+			if typ != "" && field.Type != typ {
+				continue
+			}
+			weighted := rate * searchFieldWeights[field.Field]
+			fieldScores[id] += weighted
+
+			key := field.Type + "/" + field.Slug
+			if weighted > documentScores[key] {
+				documentScores[key] = weighted
+			}
+			if documents[key] == nil {
+				documents[key] = &documentMatch{best: id}
+			}
+		}
+
+		for key, score := range documentScores {
+			documents[key].score += score
+			documents[key].matched++
+		}
+	}
+
+	for id, score := range fieldScores {
+		field := index.Fields[id]
+		doc := documents[field.Type+"/"+field.Slug]
+		if best := fieldScores[doc.best]; score > best || (score == best && id < doc.best) {
+			doc.best = id
+		}
+	}
+
+	results := make([]models.SearchResult, 0, len(documents))
+	for _, doc := range documents {
+		if doc.matched < len(terms) {
+			continue
+		}
+		field := index.Fields[doc.best]
+		results = append(results, models.SearchResult{
+			Type:  field.Type,
+			Slug:  field.Slug,
+			Field: field.Field,
+			Lang:  field.Lang,
+			Text:  field.Text,
+			Score: doc.score,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		return results[i].Slug < results[j].Slug
+	})
+
+	return results
+}
+
+func handleSearch(w http.ResponseWriter, r *http.Request) {
+	data := apiData.Load()
+
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if len(utils.Tokenize(q)) == 0 {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	typ := query.Get("type")
+	if typ != "" && typ != searchTypeQuestion && typ != searchTypeCountry {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("type must be '%s' or '%s'", searchTypeQuestion, searchTypeCountry))
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results := search(data.Search, q, typ)
+	total := len(results)
+
+	start := min(offset, total)
+	end := min(start+limit, total)
+	page := results[start:end]
+
+	response := models.PaginatedResponse{
+		Data:   page,
+		Count:  len(page),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	if end < total {
+		next := end
+		response.NextOffset = &next
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
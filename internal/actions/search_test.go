@@ -0,0 +1,135 @@
+package actions
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+func setSearchTestData(t *testing.T) {
+	t.Helper()
+	setTestQuestions(t)
+
+	data := apiData.Load()
+	data.Questions[0].I18n["fr"] = models.I18n{Title: "Capitale du Mexique", Stem: "Quelle est la capitale du Mexique ?", Explanation: "Mexico est la capitale."}
+	data.Questions[0].I18n["es"] = models.I18n{Title: "Capital de México", Stem: "¿Cuál es la capital de México?", Explanation: "Ciudad de México es la capital."}
+	data.Questions[2].I18n["en"] = models.I18n{Title: "Speed of light", Stem: "How fast is light?", Explanation: "Light travels through Mexico as fast as anywhere."}
+	data.Countries = append(data.Countries, models.Country{
+		Slug:         "mx",
+		ISOAlpha2:    "MX",
+		Name:         map[string]string{"en": "Mexico", "fr": "Mexique", "es": "México"},
+		OfficialName: map[string]string{"en": "United Mexican States", "fr": "États-Unis mexicains", "es": "Estados Unidos Mexicanos"},
+		Capital:      map[string]string{"en": "Mexico City", "fr": "Mexico", "es": "Ciudad de México"},
+	})
+	buildIndexes(data)
+}
+
+func getSearch(t *testing.T, target string) (int, []models.SearchResult) {
+	t.Helper()
+	rec := serveTestRequest(http.MethodGet, target, "")
+	var response struct {
+		Data  []models.SearchResult `json:"data"`
+		Total int                   `json:"total"`
+	}
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("invalid JSON response: %v", err)
+		}
+	}
+	return rec.Code, response.Data
+}
+
+func TestFoldText(t *testing.T) {
+	tests := map[string]string{
+		"México":           "mexico",
+		"ÉTATS-UNIS":       "etats-unis",
+		"Ciudad de México": "ciudad de mexico",
+		"Œuvre":            "oeuvre",
+		"Straße":           "strasse",
+		"São Tomé":         "sao tome",
+	}
+	for input, expected := range tests {
+		if got := utils.FoldText(input); got != expected {
+			t.Errorf("FoldText(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	setSearchTestData(t)
+
+	t.Run("accent insensitive", func(t *testing.T) {
+		status, results := getSearch(t, "/api/search?q=Mexico")
+		if status != http.StatusOK {
+			t.Fatalf("status = %d, expected %d", status, http.StatusOK)
+		}
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %+v", results)
+		}
+		if results[0].Type != searchTypeCountry || results[0].Slug != "mx" || results[0].Field != "name" {
+			t.Errorf("country name should rank first, got %+v", results[0])
+		}
+		if results[1].Slug != "history-q1" || results[1].Field != "title" || results[1].Lang != "es" {
+			t.Errorf("question title should rank before explanation, got %+v", results[1])
+		}
+		if results[2].Slug != "science-q1" || results[2].Field != "explanation" {
+			t.Errorf("explanation match should rank last, got %+v", results[2])
+		}
+	})
+
+	t.Run("all terms must match", func(t *testing.T) {
+		_, results := getSearch(t, "/api/search?q=ciudad+MÉXICO")
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results, got %+v", results)
+		}
+		if results[0].Slug != "mx" || results[0].Field != "capital" || results[0].Text != "Ciudad de México" {
+			t.Errorf("unexpected first result: %+v", results[0])
+		}
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		_, results := getSearch(t, "/api/search?q=mexic")
+		if len(results) != 3 {
+			t.Errorf("expected 3 results for prefix, got %+v", results)
+		}
+		_, results = getSearch(t, "/api/search?q=me")
+		if len(results) != 0 {
+			t.Errorf("short terms should not match as prefixes, got %+v", results)
+		}
+	})
+
+	t.Run("type filter", func(t *testing.T) {
+		_, results := getSearch(t, "/api/search?q=mexico&type=question")
+		for _, r := range results {
+			if r.Type != searchTypeQuestion {
+				t.Errorf("unexpected result type %s", r.Type)
+			}
+		}
+		if len(results) != 2 {
+			t.Errorf("expected 2 question results, got %d", len(results))
+		}
+	})
+
+	t.Run("answer labels", func(t *testing.T) {
+		_, results := getSearch(t, "/api/search?q=art-q1-answer-2")
+		if len(results) != 1 || results[0].Field != "answer" || results[0].Slug != "art-q1" {
+			t.Errorf("expected answer label match, got %+v", results)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		_, results := getSearch(t, "/api/search?q=mexico&limit=1&offset=1")
+		if len(results) != 1 || results[0].Slug != "history-q1" {
+			t.Errorf("unexpected page: %+v", results)
+		}
+	})
+
+	for _, target := range []string{"/api/search", "/api/search?q=+", "/api/search?q=x&type=region", "/api/search?q=x&limit=0"} {
+		if status, _ := getSearch(t, target); status != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, expected %d", target, status, http.StatusBadRequest)
+		}
+	}
+}
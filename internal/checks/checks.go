@@ -2,85 +2,133 @@ package checks
 
 import (
 	"fmt"
+	"math"
 	"net/url"
 	"strings"
 
 	"cultpedia/internal/models"
 	"cultpedia/internal/utils"
+	"cultpedia/pkg/textmatch"
 )
 
-func ValidateQuestions() error {
+// Bounds of estimated_seconds. The question schema and CONTRIBUTING.md have
+// always capped it at 30; validateQuestion used to accept up to 300, so
+// validate and the schema check disagreed.
+const (
+	minEstimatedSeconds = 5
+	maxEstimatedSeconds = 30
+)
+
+func ValidateQuestions() *Report {
+	r := NewReport("validate")
+	checkSchemas(r, questionSchemaTargets)
+
 	questions, err := utils.LoadQuestions()
 	if err != nil {
-		return err
+		r.addError("load-error", utils.QuestionsFile, 0, "", err)
+		return r
 	}
+	checkQuestionSet(r, utils.QuestionsFile, questions)
+	checkMedia(r, utils.QuestionsFile, utils.QuestionAssetsDir, questions)
+	return r
+}
+
+func ValidateQuestionSet(questions []models.Question) error {
+	r := NewReport("validate")
+	checkQuestionSet(r, "", questions)
+	return r.Err()
+}
+
+func checkQuestionSet(r *Report, file string, questions []models.Question) {
 	slugs := make(map[string]bool)
-	var errors []string
 
 	for i, q := range questions {
 		if err := validateQuestion(q); err != nil {
-			errors = append(errors, fmt.Sprintf("line %d (slug: %s): %v", i+1, q.Slug, err))
+			r.addError("invalid-question", file, i+1, q.Slug, err)
 			continue
 		}
 		if slugs[q.Slug] {
-			errors = append(errors, fmt.Sprintf("duplicate detected for slug '%s' at line %d", q.Slug, i+1))
+			r.Add(Finding{Rule: "duplicate-slug", File: file, Line: i + 1, Slug: q.Slug, Path: "/slug", Message: fmt.Sprintf("duplicate detected for slug '%s'", q.Slug)})
 		} else {
 			slugs[q.Slug] = true
 		}
 	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("validation errors:\n%s", strings.Join(errors, "\n"))
-	}
-
-	return nil
 }
 
 func validateQuestion(q models.Question) error {
 	if q.Kind != "question" {
-		return fmt.Errorf("kind must be 'question'")
+		return fieldErrorf("/kind", "kind must be 'question'")
 	}
 	if q.Slug == "" {
-		return fmt.Errorf("slug is required")
+		return fieldErrorf("/slug", "slug is required")
 	}
 	if !isValidSlug(q.Slug) {
-		return fmt.Errorf("slug must be lowercase with hyphens only (got '%s')", q.Slug)
+		return fieldErrorf("/slug", "slug must be lowercase with hyphens only (got '%s')", q.Slug)
 	}
 	if q.Theme.Slug == "" {
-		return fmt.Errorf("theme.slug is required")
+		return fieldErrorf("/theme/slug", "theme.slug is required")
+	}
+
+	validQtypes := []string{"single_choice", "multiple_choice", "true_false", "ordering", "numeric", "text_input"}
+	if !utils.Contains(validQtypes, q.Qtype) {
+		return fieldErrorf("/qtype", "qtype must be one of: %s (got '%s')", strings.Join(validQtypes, ", "), q.Qtype)
 	}
 
-	validQtypes := []string{"single_choice", "true_false"}
-	if !contains(validQtypes, q.Qtype) {
-		return fmt.Errorf("qtype must be one of: %s (got '%s')", strings.Join(validQtypes, ", "), q.Qtype)
+	if q.Scoring != "" {
+		validScorings := []string{"all_or_nothing", "partial"}
+		if q.Qtype != "multiple_choice" && q.Qtype != "ordering" && q.Qtype != "numeric" {
+			return fieldErrorf("/scoring", "scoring is only allowed on multiple_choice, ordering and numeric questions")
+		}
+		if !utils.Contains(validScorings, q.Scoring) {
+			return fieldErrorf("/scoring", "scoring must be one of: %s (got '%s')", strings.Join(validScorings, ", "), q.Scoring)
+		}
+	}
+
+	if q.TypoTolerance != 0 {
+		if q.Qtype != "text_input" {
+			return fieldErrorf("/typo_tolerance", "typo_tolerance is only allowed on text_input questions")
+		}
+		if q.TypoTolerance < 0 || q.TypoTolerance > 3 {
+			return fieldErrorf("/typo_tolerance", "typo_tolerance must be between 0 and 3 (got %d)", q.TypoTolerance)
+		}
 	}
 
 	validDifficulties := []string{"beginner", "intermediate", "advanced", "pro"}
-	if !contains(validDifficulties, q.Difficulty) {
-		return fmt.Errorf("difficulty must be one of: %s (got '%s')", strings.Join(validDifficulties, ", "), q.Difficulty)
+	if !utils.Contains(validDifficulties, q.Difficulty) {
+		return fieldErrorf("/difficulty", "difficulty must be one of: %s (got '%s')", strings.Join(validDifficulties, ", "), q.Difficulty)
 	}
 
 	if q.Points < 0.5 || q.Points > 5.0 {
-		return fmt.Errorf("points must be between 0.5 and 5.0 (got %.1f)", q.Points)
+		return fieldErrorf("/points", "points must be between 0.5 and 5.0 (got %.1f)", q.Points)
 	}
 
-	if q.EstimatedSeconds < 5 || q.EstimatedSeconds > 300 {
-		return fmt.Errorf("estimated_seconds must be between 5 and 300 (got %d)", q.EstimatedSeconds)
+	if q.EstimatedSeconds < minEstimatedSeconds || q.EstimatedSeconds > maxEstimatedSeconds {
+		return fieldErrorf("/estimated_seconds", "estimated_seconds must be between %d and %d (got %d)", minEstimatedSeconds, maxEstimatedSeconds, q.EstimatedSeconds)
 	}
 
 	if len(q.Sources) == 0 {
-		return fmt.Errorf("at least one source URL is required")
+		return fieldErrorf("/sources", "at least one source URL is required")
 	}
 
 	for i, source := range q.Sources {
 		if err := validateURL(source); err != nil {
-			return fmt.Errorf("invalid source URL #%d (%s): %v", i+1, source, err)
+			return fieldErrorf(fmt.Sprintf("/sources/%d", i), "invalid source URL #%d (%s): %v", i+1, source, err)
 		}
 	}
 
+	if q.Media != nil {
+		if err := validateMedia(*q.Media); err != nil {
+			return err
+		}
+	}
+
+	if q.Flag != "" && (len(q.Flag) != 2 || strings.Trim(q.Flag, "abcdefghijklmnopqrstuvwxyz") != "") {
+		return fieldErrorf("/flag", "flag must be a lowercase ISO alpha-2 country code (got '%s')", q.Flag)
+	}
+
 	if q.Qtype == "true_false" {
 		if len(q.Answers) != 2 {
-			return fmt.Errorf("true_false questions must have exactly 2 answers (got %d)", len(q.Answers))
+			return fieldErrorf("/answers", "true_false questions must have exactly 2 answers (got %d)", len(q.Answers))
 		}
 		hasTrue, hasFalse := false, false
 		for _, a := range q.Answers {
@@ -92,39 +140,153 @@ func validateQuestion(q models.Question) error {
 			}
 		}
 		if !hasTrue || !hasFalse {
-			return fmt.Errorf("true_false questions must have answers with slugs 'true' and 'false'")
+			return fieldErrorf("/answers", "true_false questions must have answers with slugs 'true' and 'false'")
+		}
+	} else if q.Qtype == "multiple_choice" {
+		if len(q.Answers) < 2 || len(q.Answers) > 6 {
+			return fieldErrorf("/answers", "multiple_choice questions must have between 2 and 6 answers (got %d)", len(q.Answers))
+		}
+	} else if q.Qtype == "numeric" {
+		if len(q.Answers) != 0 {
+			return fieldErrorf("/answers", "numeric questions take their answer from numeric, answers must be empty (got %d)", len(q.Answers))
+		}
+		if err := validateNumeric(q); err != nil {
+			return err
+		}
+	} else if q.Qtype == "text_input" {
+		if len(q.Answers) < 1 || len(q.Answers) > 6 {
+			return fieldErrorf("/answers", "text_input questions must have between 1 and 6 answers (got %d)", len(q.Answers))
+		}
+	} else if q.Qtype == "ordering" {
+		if len(q.Answers) < 3 || len(q.Answers) > 6 {
+			return fieldErrorf("/answers", "ordering questions must have between 3 and 6 answers (got %d)", len(q.Answers))
 		}
 	} else {
 		if len(q.Answers) != 4 {
-			return fmt.Errorf("must have exactly 4 answers (got %d)", len(q.Answers))
+			return fieldErrorf("/answers", "must have exactly 4 answers (got %d)", len(q.Answers))
 		}
 	}
 
 	correctCount := 0
-	for _, a := range q.Answers {
+	for i, a := range q.Answers {
 		if a.IsCorrect {
 			correctCount++
 		}
 		if a.Slug == "" {
-			return fmt.Errorf("answer slug is required")
+			return fieldErrorf(fmt.Sprintf("/answers/%d/slug", i), "answer slug is required")
 		}
 	}
-	if correctCount != 1 {
-		return fmt.Errorf("must have exactly one correct answer")
+	switch q.Qtype {
+	case "numeric":
+	case "ordering":
+		if err := validatePositions(q.Answers); err != nil {
+			return err
+		}
+	case "multiple_choice":
+		if correctCount == 0 {
+			return fieldErrorf("/answers", "must have at least one correct answer")
+		}
+	case "text_input":
+		if correctCount != len(q.Answers) {
+			return fieldErrorf("/answers", "every text_input answer must be correct")
+		}
+	default:
+		if correctCount != 1 {
+			return fieldErrorf("/answers", "must have exactly one correct answer")
+		}
+	}
+	if q.Qtype != "numeric" && q.Numeric != nil {
+		return fieldErrorf("/numeric", "numeric is only allowed on numeric questions")
+	}
+	if q.Qtype != "ordering" {
+		for i, a := range q.Answers {
+			if a.Position != 0 {
+				return fieldErrorf(fmt.Sprintf("/answers/%d/position", i), "position is only allowed on ordering questions")
+			}
+		}
 	}
 	requiredLangs := []string{"fr", "en", "es"}
 	for _, lang := range requiredLangs {
 		if _, ok := q.I18n[lang]; !ok {
-			return fmt.Errorf("missing %s translation in question", lang)
+			return fieldErrorf("/i18n/"+lang, "missing %s translation in question", lang)
 		}
-		for _, a := range q.Answers {
+		for i, a := range q.Answers {
 			if _, ok := a.I18n[lang]; !ok {
-				return fmt.Errorf("missing %s translation in answer %s", lang, a.Slug)
+				return fieldErrorf(fmt.Sprintf("/answers/%d/i18n/%s", i, lang), "missing %s translation in answer %s", lang, a.Slug)
 			}
 		}
 	}
+	return validateAccepted(q, requiredLangs)
+}
+
+// validateAccepted requires every text_input answer to list at least one
+// accepted variant per language.
+func validateAccepted(q models.Question, langs []string) error {
+	for i, a := range q.Answers {
+		if q.Qtype != "text_input" {
+			if a.Accepted != nil {
+				return fieldErrorf(fmt.Sprintf("/answers/%d/accepted", i), "accepted is only allowed on text_input questions")
+			}
+			continue
+		}
+		for _, lang := range langs {
+			found := false
+			for j, variant := range a.Accepted[lang] {
+				if textmatch.Normalize(variant, lang) == "" {
+					return fieldErrorf(fmt.Sprintf("/answers/%d/accepted/%s/%d", i, lang, j), "accepted variant '%s' has no letters or digits", variant)
+				}
+				found = true
+			}
+			if !found {
+				return fieldErrorf(fmt.Sprintf("/answers/%d/accepted/%s", i, lang), "answer %s needs at least one accepted %s variant", a.Slug, lang)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePositions requires the answers of an ordering question to use each
+// position from 1 to len(answers) exactly once.
+func validatePositions(answers []models.Answer) error {
+	seen := make(map[int]string, len(answers))
+	for i, a := range answers {
+		if a.IsCorrect {
+			return fieldErrorf(fmt.Sprintf("/answers/%d/is_correct", i), "ordering answers use position instead of is_correct")
+		}
+		if a.Position < 1 || a.Position > len(answers) {
+			return fieldErrorf(fmt.Sprintf("/answers/%d/position", i), "position must be between 1 and %d (got %d)", len(answers), a.Position)
+		}
+		if other, ok := seen[a.Position]; ok {
+			return fieldErrorf(fmt.Sprintf("/answers/%d/position", i), "position %d is already used by answer %s", a.Position, other)
+		}
+		seen[a.Position] = a.Slug
+	}
+	return nil
+}
+
+func validateNumeric(q models.Question) error {
+	n := q.Numeric
+	if n == nil {
+		return fieldErrorf("/numeric", "numeric questions must have a numeric answer")
+	}
+	if math.IsNaN(n.Value) || math.IsInf(n.Value, 0) {
+		return fieldErrorf("/numeric/value", "value must be a finite number")
+	}
+	if n.Tolerance < 0 {
+		return fieldErrorf("/numeric/tolerance", "tolerance must not be negative (got %g)", n.Tolerance)
+	}
+	if n.RelativeTolerance < 0 || n.RelativeTolerance >= 1 {
+		return fieldErrorf("/numeric/relative_tolerance", "relative_tolerance must be between 0 and 1 (got %g)", n.RelativeTolerance)
+	}
+	if n.Tolerance > 0 && n.RelativeTolerance > 0 {
+		return fieldErrorf("/numeric", "use either tolerance or relative_tolerance, not both")
+	}
+	if q.Scoring == "partial" && n.Tolerance == 0 && n.RelativeTolerance == 0 {
+		return fieldErrorf("/scoring", "partial scoring needs a tolerance or relative_tolerance")
+	}
 	return nil
 }
+
 func isValidSlug(slug string) bool {
 	if slug == "" {
 		return false
@@ -143,15 +305,6 @@ func isValidSlug(slug string) bool {
 	return true
 }
 
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
-
 func validateURL(rawURL string) error {
 	if rawURL == "" {
 		return fmt.Errorf("URL cannot be empty")
@@ -215,52 +368,31 @@ func ValidateQuestionStrict(q models.Question) error {
 	return nil
 }
 
-func CheckDuplicates() string {
+func CheckDuplicates() *Report {
+	r := NewReport("check-duplicates")
 	questions, err := utils.LoadQuestions()
 	if err != nil {
-		return fmt.Sprintf("error: %v", err)
+		r.addError("load-error", utils.QuestionsFile, 0, "", err)
+		return r
 	}
 	slugs := make(map[string]int)
-	var duplicates []string
 	for i, q := range questions {
 		if firstLine, exists := slugs[q.Slug]; exists {
-			duplicates = append(duplicates, fmt.Sprintf("slug '%s' duplicated: first occurrence line %d, occurrence line %d", q.Slug, firstLine+1, i+1))
+			r.Add(Finding{Rule: "duplicate-slug", File: utils.QuestionsFile, Line: i + 1, Slug: q.Slug, Path: "/slug", Message: fmt.Sprintf("slug '%s' duplicated: first occurrence line %d", q.Slug, firstLine+1)})
 		} else {
 			slugs[q.Slug] = i
 		}
 	}
-	if len(duplicates) > 0 {
-		return fmt.Sprintf("duplicates detected:\n%s", strings.Join(duplicates, "\n"))
-	} else {
-		return "No duplicates."
-	}
+	return r
 }
 
-func CheckTranslations() string {
+func CheckTranslations() *Report {
+	r := NewReport("check-translations")
 	questions, err := utils.LoadQuestions()
 	if err != nil {
-		return fmt.Sprintf("error: %v", err)
-	}
-	valid := true
-	var missing []string
-	for i, q := range questions {
-		langs := []string{"fr", "en", "es"}
-		for _, lang := range langs {
-			if _, ok := q.I18n[lang]; !ok {
-				valid = false
-				missing = append(missing, fmt.Sprintf("question line %d (slug: %s): missing %s translation in title/question/explanation", i+1, q.Slug, lang))
-			}
-			for j, a := range q.Answers {
-				if _, ok := a.I18n[lang]; !ok {
-					valid = false
-					missing = append(missing, fmt.Sprintf("answer %d of question line %d (slug: %s): missing %s translation", j+1, i+1, q.Slug, lang))
-				}
-			}
-		}
-	}
-	if valid {
-		return "All translations present."
-	} else {
-		return fmt.Sprintf("missing translations:\n%s", strings.Join(missing, "\n"))
+		r.addError("load-error", utils.QuestionsFile, 0, "", err)
+		return r
 	}
+	checkTranslations(r, utils.QuestionsFile, questions)
+	return r
 }
@@ -4,11 +4,16 @@ import (
 	"testing"
 
 	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
 )
 
 const (
-	qtypeSingleChoice = "single_choice"
-	qtypeTrueFalse    = "true_false"
+	qtypeSingleChoice   = "single_choice"
+	qtypeTrueFalse      = "true_false"
+	qtypeMultipleChoice = "multiple_choice"
+	qtypeOrdering       = "ordering"
+	qtypeNumeric        = "numeric"
+	qtypeTextInput      = "text_input"
 )
 
 func TestIsValidSlug(t *testing.T) {
@@ -56,9 +61,9 @@ func TestContains(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := contains(tt.slice, tt.item)
+			result := utils.Contains(tt.slice, tt.item)
 			if result != tt.expected {
-				t.Errorf("contains(%v, %q) = %v, expected %v", tt.slice, tt.item, result, tt.expected)
+				t.Errorf("utils.Contains(%v, %q) = %v, expected %v", tt.slice, tt.item, result, tt.expected)
 			}
 		})
 	}
@@ -187,6 +192,18 @@ func TestValidateQuestion(t *testing.T) {
 		}
 	})
 
+	t.Run("flag", func(t *testing.T) {
+		q := createValidQuestion()
+		q.Flag = "fr"
+		if err := validateQuestion(q); err != nil {
+			t.Errorf("validateQuestion() returned unexpected error for a flag: %v", err)
+		}
+		q.Flag = "FRA"
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error for a flag that is not an alpha-2 code")
+		}
+	})
+
 	t.Run("wrong answer count for single_choice", func(t *testing.T) {
 		q := createValidQuestion()
 		q.Answers = q.Answers[:2]
@@ -263,3 +280,220 @@ func TestValidateTrueFalseQuestion(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateMultipleChoiceQuestion(t *testing.T) {
+	multipleChoice := func() models.Question {
+		q := createValidQuestion()
+		q.Qtype = qtypeMultipleChoice
+		q.Answers[1].IsCorrect = true
+		return q
+	}
+
+	t.Run("valid multiple_choice question", func(t *testing.T) {
+		for _, scoring := range []string{"", "all_or_nothing", "partial"} {
+			q := multipleChoice()
+			q.Scoring = scoring
+			if err := validateQuestion(q); err != nil {
+				t.Errorf("validateQuestion() returned unexpected error for scoring '%s': %v", scoring, err)
+			}
+		}
+	})
+
+	t.Run("answer count", func(t *testing.T) {
+		q := multipleChoice()
+		q.Answers = q.Answers[:2]
+		if err := validateQuestion(q); err != nil {
+			t.Errorf("validateQuestion() returned unexpected error for 2 answers: %v", err)
+		}
+
+		q = multipleChoice()
+		q.Answers = append(q.Answers, q.Answers[2], q.Answers[3], q.Answers[3])
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error for more than 6 answers")
+		}
+	})
+
+	t.Run("no correct answer", func(t *testing.T) {
+		q := multipleChoice()
+		for i := range q.Answers {
+			q.Answers[i].IsCorrect = false
+		}
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error when no answer is correct")
+		}
+	})
+
+	t.Run("invalid scoring", func(t *testing.T) {
+		q := multipleChoice()
+		q.Scoring = "proportional"
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error for unknown scoring")
+		}
+
+		q = createValidQuestion()
+		q.Scoring = "partial"
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error for scoring on a single_choice question")
+		}
+	})
+
+	t.Run("single_choice keeps exactly one correct answer", func(t *testing.T) {
+		q := multipleChoice()
+		q.Qtype = qtypeSingleChoice
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error for single_choice with 2 correct answers")
+		}
+	})
+}
+
+func TestValidateOrderingQuestion(t *testing.T) {
+	ordering := func() models.Question {
+		q := createValidQuestion()
+		q.Qtype = qtypeOrdering
+		q.Scoring = "partial"
+		for i := range q.Answers {
+			q.Answers[i].IsCorrect = false
+			q.Answers[i].Position = len(q.Answers) - i
+		}
+		return q
+	}
+
+	t.Run("valid ordering question", func(t *testing.T) {
+		if err := validateQuestion(ordering()); err != nil {
+			t.Errorf("validateQuestion() returned unexpected error for ordering: %v", err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		modify func(q *models.Question)
+	}{
+		{"duplicate position", func(q *models.Question) { q.Answers[0].Position = q.Answers[1].Position }},
+		{"gap in positions", func(q *models.Question) { q.Answers[0].Position = 5 }},
+		{"missing position", func(q *models.Question) { q.Answers[2].Position = 0 }},
+		{"correct answer", func(q *models.Question) { q.Answers[0].IsCorrect = true }},
+		{"too few answers", func(q *models.Question) { q.Answers = q.Answers[2:] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ordering()
+			tt.modify(&q)
+			if err := validateQuestion(q); err == nil {
+				t.Error("validateQuestion() should return error")
+			}
+		})
+	}
+
+	t.Run("position on a single_choice question", func(t *testing.T) {
+		q := createValidQuestion()
+		q.Answers[0].Position = 1
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error for position outside ordering questions")
+		}
+	})
+}
+
+func TestValidateNumericQuestion(t *testing.T) {
+	numeric := func() models.Question {
+		q := createValidQuestion()
+		q.Qtype = qtypeNumeric
+		q.Answers = []models.Answer{}
+		q.Numeric = &models.NumericAnswer{Value: 1789}
+		return q
+	}
+
+	valid := map[string]func(q *models.Question){
+		"exact value":        func(q *models.Question) {},
+		"absolute tolerance": func(q *models.Question) { q.Numeric.Tolerance = 5; q.Scoring = "partial" },
+		"relative tolerance": func(q *models.Question) { q.Numeric.RelativeTolerance = 0.1; q.Numeric.Unit = "km²" },
+	}
+	for name, modify := range valid {
+		t.Run(name, func(t *testing.T) {
+			q := numeric()
+			modify(&q)
+			if err := validateQuestion(q); err != nil {
+				t.Errorf("validateQuestion() returned unexpected error: %v", err)
+			}
+		})
+	}
+
+	invalid := map[string]func(q *models.Question){
+		"missing numeric":           func(q *models.Question) { q.Numeric = nil },
+		"with answers":              func(q *models.Question) { q.Answers = createValidQuestion().Answers },
+		"negative tolerance":        func(q *models.Question) { q.Numeric.Tolerance = -1 },
+		"relative tolerance of 1":   func(q *models.Question) { q.Numeric.RelativeTolerance = 1 },
+		"both tolerances":           func(q *models.Question) { q.Numeric.Tolerance = 1; q.Numeric.RelativeTolerance = 0.1 },
+		"partial without tolerance": func(q *models.Question) { q.Scoring = "partial" },
+	}
+	for name, modify := range invalid {
+		t.Run(name, func(t *testing.T) {
+			q := numeric()
+			modify(&q)
+			if err := validateQuestion(q); err == nil {
+				t.Error("validateQuestion() should return error")
+			}
+		})
+	}
+
+	t.Run("numeric on a single_choice question", func(t *testing.T) {
+		q := createValidQuestion()
+		q.Numeric = &models.NumericAnswer{Value: 1}
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error for numeric outside numeric questions")
+		}
+	})
+}
+
+func TestValidateTextInputQuestion(t *testing.T) {
+	textInput := func() models.Question {
+		q := createValidQuestion()
+		q.Qtype = qtypeTextInput
+		q.TypoTolerance = 1
+		q.Answers = []models.Answer{{
+			Slug:      "leonardo-da-vinci",
+			IsCorrect: true,
+			I18n:      map[string]models.Label{"fr": {Label: "Léonard de Vinci"}, "en": {Label: "Leonardo da Vinci"}, "es": {Label: "Leonardo da Vinci"}},
+			Accepted:  map[string][]string{"fr": {"Vinci"}, "en": {"da Vinci"}, "es": {"Da Vinci"}},
+		}}
+		return q
+	}
+
+	if err := validateQuestion(textInput()); err != nil {
+		t.Errorf("validateQuestion() returned unexpected error: %v", err)
+	}
+
+	invalid := map[string]func(q *models.Question){
+		"missing language":      func(q *models.Question) { delete(q.Answers[0].Accepted, "es") },
+		"empty variants":        func(q *models.Question) { q.Answers[0].Accepted["fr"] = []string{} },
+		"variant without words": func(q *models.Question) { q.Answers[0].Accepted["en"] = []string{"?!"} },
+		"incorrect answer":      func(q *models.Question) { q.Answers[0].IsCorrect = false },
+		"no answers":            func(q *models.Question) { q.Answers = nil },
+		"typo tolerance of 4":   func(q *models.Question) { q.TypoTolerance = 4 },
+		"scoring":               func(q *models.Question) { q.Scoring = "partial" },
+	}
+	for name, modify := range invalid {
+		t.Run(name, func(t *testing.T) {
+			q := textInput()
+			modify(&q)
+			if err := validateQuestion(q); err == nil {
+				t.Error("validateQuestion() should return error")
+			}
+		})
+	}
+
+	t.Run("accepted on a single_choice question", func(t *testing.T) {
+		q := createValidQuestion()
+		q.Answers[0].Accepted = map[string][]string{"en": {"A"}}
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error for accepted outside text_input questions")
+		}
+	})
+
+	t.Run("typo tolerance on a single_choice question", func(t *testing.T) {
+		q := createValidQuestion()
+		q.TypoTolerance = 1
+		if err := validateQuestion(q); err == nil {
+			t.Error("validateQuestion() should return error for typo_tolerance outside text_input questions")
+		}
+	})
+}
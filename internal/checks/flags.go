@@ -0,0 +1,200 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cultpedia/internal/models"
+)
+
+const (
+	MaxFlagSize  = 256 * 1024
+	svgNamespace = "http://www.w3.org/2000/svg"
+)
+
+// The API serves flags as image/svg+xml, so anything that can run code or
+// pull in another resource is rejected.
+var unsafeSVGElements = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"embed":         true,
+	"object":        true,
+	"audio":         true,
+	"video":         true,
+	"handler":       true,
+	"listener":      true,
+}
+
+func checkFlags(r *Report, file, dir string, countries []models.Country) {
+	referenced := make(map[string]bool)
+	for i, c := range countries {
+		if c.Flag == "" {
+			continue
+		}
+		referenced[c.Flag+".svg"] = true
+
+		flagPath := filepath.Join(dir, c.Flag+".svg")
+		data, err := os.ReadFile(flagPath)
+		if os.IsNotExist(err) {
+			r.Add(Finding{Rule: "missing-flag", File: file, Line: i + 1, Slug: c.Slug, Path: "/flag", Message: fmt.Sprintf("missing flag file %s", flagPath)})
+			continue
+		}
+		if err != nil {
+			r.addError("load-error", flagPath, 0, c.Slug, err)
+			continue
+		}
+
+		if len(data) > MaxFlagSize {
+			r.Add(Finding{Rule: "flag-size", File: flagPath, Slug: c.Slug, Message: fmt.Sprintf("flag is %d bytes, the limit is %d", len(data), MaxFlagSize)})
+		}
+		for _, f := range inspectSVG(data) {
+			f.File, f.Slug = flagPath, c.Slug
+			r.Add(f)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var orphans []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".svg") && !referenced[entry.Name()] {
+			orphans = append(orphans, entry.Name())
+		}
+	}
+	sort.Strings(orphans)
+	for _, name := range orphans {
+		r.Add(Finding{Rule: "orphan-flag", Severity: SeverityWarning, File: filepath.Join(dir, name), Message: "no country references this flag"})
+	}
+}
+
+func inspectSVG(data []byte) []Finding {
+	var findings []Finding
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	add := func(rule, format string, args ...interface{}) {
+		line, _ := decoder.InputPos()
+		findings = append(findings, Finding{Rule: rule, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	root := true
+	inStyle := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			add("invalid-flag", "not well-formed XML: %v", err)
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.Directive:
+			add("unsafe-flag", "DOCTYPE and ENTITY declarations are not allowed")
+		case xml.ProcInst:
+			if t.Target != "xml" {
+				add("unsafe-flag", "processing instruction <?%s?> is not allowed", t.Target)
+			}
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if root {
+				root = false
+				if name != "svg" || t.Name.Space != svgNamespace {
+					add("invalid-flag", "root element must be <svg xmlns=\"%s\">, got <%s>", svgNamespace, t.Name.Local)
+				} else if err := checkViewBox(t.Attr); err != nil {
+					add("invalid-flag", "%v", err)
+				}
+			}
+			if unsafeSVGElements[name] {
+				add("unsafe-flag", "<%s> elements are not allowed", t.Name.Local)
+			}
+			inStyle = name == "style"
+			for _, attr := range t.Attr {
+				local := strings.ToLower(attr.Name.Local)
+				switch {
+				case strings.HasPrefix(local, "on"):
+					add("unsafe-flag", "event handler attribute %s is not allowed", attr.Name.Local)
+				case local == "href" && !strings.HasPrefix(strings.TrimSpace(attr.Value), "#"):
+					add("unsafe-flag", "external reference %s=\"%s\" is not allowed", attr.Name.Local, attr.Value)
+				default:
+					if ref := externalCSSReference(attr.Value); ref != "" {
+						add("unsafe-flag", "external reference %s in %s is not allowed", ref, attr.Name.Local)
+					}
+				}
+			}
+		case xml.EndElement:
+			inStyle = false
+		case xml.CharData:
+			if inStyle {
+				if ref := externalCSSReference(string(t)); ref != "" {
+					add("unsafe-flag", "external reference %s in <style> is not allowed", ref)
+				}
+			}
+		}
+	}
+
+	if root && len(findings) == 0 {
+		add("invalid-flag", "file has no <svg> element")
+	}
+	return findings
+}
+
+func checkViewBox(attrs []xml.Attr) error {
+	for _, attr := range attrs {
+		if attr.Name.Local != "viewBox" {
+			continue
+		}
+		fields := strings.Fields(strings.ReplaceAll(attr.Value, ",", " "))
+		if len(fields) != 4 {
+			return fmt.Errorf("viewBox '%s' must have 4 numbers", attr.Value)
+		}
+		var values [4]float64
+		for i, field := range fields {
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return fmt.Errorf("viewBox '%s' must have 4 numbers", attr.Value)
+			}
+			values[i] = v
+		}
+		if values[2] <= 0 || values[3] <= 0 {
+			return fmt.Errorf("viewBox '%s' must have a positive width and height", attr.Value)
+		}
+		return nil
+	}
+	return fmt.Errorf("root <svg> must have a viewBox")
+}
+
+func externalCSSReference(value string) string {
+	lower := strings.ToLower(value)
+	if strings.Contains(lower, "@import") {
+		return "@import"
+	}
+	if strings.Contains(lower, "javascript:") {
+		return "javascript:"
+	}
+	for rest := lower; ; {
+		i := strings.Index(rest, "url(")
+		if i < 0 {
+			return ""
+		}
+		rest = rest[i+len("url("):]
+		target := strings.Trim(strings.TrimSpace(rest), `'"`)
+		if !strings.HasPrefix(target, "#") {
+			end := strings.IndexByte(rest, ')')
+			if end < 0 {
+				end = len(rest)
+			}
+			return "url(" + strings.TrimSpace(rest[:end]) + ")"
+		}
+	}
+}
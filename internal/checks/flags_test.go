@@ -0,0 +1,100 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cultpedia/internal/models"
+)
+
+const validTestFlag = `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 640 480">
+  <defs><path id="a" d="M0 0h10v10H0z"/><linearGradient id="g"/></defs>
+  <style>.x { fill: url(#g) }</style>
+  <use xlink:href="#a" fill="url(#g)"/>
+</svg>`
+
+func TestInspectSVG(t *testing.T) {
+	if findings := inspectSVG([]byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + validTestFlag)); len(findings) != 0 {
+		t.Fatalf("valid flag should have no findings, got %+v", findings)
+	}
+
+	tests := []struct {
+		name string
+		svg  string
+		rule string
+		line int
+	}{
+		{"not xml", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1">`, "invalid-flag", 1},
+		{"empty", ``, "invalid-flag", 1},
+		{"html root", `<html><body/></html>`, "invalid-flag", 1},
+		{"missing viewBox", `<svg xmlns="http://www.w3.org/2000/svg" width="640" height="480"/>`, "invalid-flag", 1},
+		{"empty viewBox", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 0 480"/>`, "invalid-flag", 1},
+		{"script", "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 1 1\">\n<script>alert(1)</script></svg>", "unsafe-flag", 2},
+		{"foreignObject", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1"><foreignObject/></svg>`, "unsafe-flag", 1},
+		{"event handler", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1" onload="alert(1)"/>`, "unsafe-flag", 1},
+		{"external image", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1"><image href="https://example.com/a.png"/></svg>`, "unsafe-flag", 1},
+		{"external use", `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 1 1"><use xlink:href="other.svg#a"/></svg>`, "unsafe-flag", 1},
+		{"css url", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1"><path style="fill: url('https://example.com/x')"/></svg>`, "unsafe-flag", 1},
+		{"css import", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1"><style>@import "x.css";</style></svg>`, "unsafe-flag", 1},
+		{"doctype", "<!DOCTYPE svg [<!ENTITY x \"y\">]>\n<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 1 1\"/>", "unsafe-flag", 1},
+		{"stylesheet", `<?xml-stylesheet href="x.css"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1"/>`, "unsafe-flag", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := inspectSVG([]byte(tt.svg))
+			if len(findings) != 1 {
+				t.Fatalf("expected 1 finding, got %+v", findings)
+			}
+			if findings[0].Rule != tt.rule || findings[0].Line != tt.line {
+				t.Errorf("got %s at line %d, expected %s at line %d", findings[0].Rule, findings[0].Line, tt.rule, tt.line)
+			}
+		})
+	}
+}
+
+func TestCheckFlags(t *testing.T) {
+	dir := t.TempDir()
+	writeFlag := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFlag("fr.svg", validTestFlag)
+	writeFlag("be.svg", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1" onclick="x()"/>`)
+	writeFlag("jp.svg", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1"><!--`+strings.Repeat("x", MaxFlagSize)+`--></svg>`)
+	writeFlag("zz.svg", validTestFlag)
+
+	countries := []models.Country{
+		{Slug: "fr", Flag: "fr"},
+		{Slug: "be", Flag: "be"},
+		{Slug: "de", Flag: "de"},
+		{Slug: "jp", Flag: "jp"},
+		{Slug: "aq"},
+	}
+
+	r := NewReport("validate-geography")
+	checkFlags(r, "countries.ndjson", dir, countries)
+
+	expected := []Finding{
+		{Rule: "unsafe-flag", Severity: SeverityError, File: filepath.Join(dir, "be.svg"), Line: 1, Slug: "be"},
+		{Rule: "missing-flag", Severity: SeverityError, File: "countries.ndjson", Line: 3, Slug: "de", Path: "/flag"},
+		{Rule: "flag-size", Severity: SeverityError, File: filepath.Join(dir, "jp.svg"), Slug: "jp"},
+		{Rule: "orphan-flag", Severity: SeverityWarning, File: filepath.Join(dir, "zz.svg")},
+	}
+	if len(r.Findings) != len(expected) {
+		t.Fatalf("expected %d findings, got %+v", len(expected), r.Findings)
+	}
+	for i, e := range expected {
+		got := r.Findings[i]
+		got.Message = ""
+		if got != e {
+			t.Errorf("finding %d = %+v, expected %+v", i, got, e)
+		}
+	}
+	if !r.HasErrors() {
+		t.Error("missing and unsafe flags should be errors")
+	}
+}
@@ -2,333 +2,431 @@ package checks
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"math"
+	"strconv"
 	"strings"
 
 	"cultpedia/internal/models"
 	"cultpedia/internal/utils"
 )
 
-func ValidateGeography() error {
-	var allErrors []string
-	var warnings []string
+const DefaultAggregateTolerance = 0.01
 
-	if err := ValidateCountries(); err != nil {
-		allErrors = append(allErrors, fmt.Sprintf("Countries validation failed:\n%v", err))
-	}
-
-	if err := ValidateContinents(); err != nil {
-		allErrors = append(allErrors, fmt.Sprintf("Continents validation failed:\n%v", err))
-	}
+func ValidateGeography(tolerance float64) *Report {
+	r := NewReport("validate-geography")
+	checkSchemas(r, geographySchemaTargets)
+	loaded := true
 
-	if err := ValidateRegions(); err != nil {
-		allErrors = append(allErrors, fmt.Sprintf("Regions validation failed:\n%v", err))
+	countries, err := utils.LoadCountries()
+	if err != nil {
+		r.addError("load-error", utils.CountriesFile, 0, "", err)
+		loaded = false
+	} else {
+		checkCountrySet(r, utils.CountriesFile, countries)
+		checkCountryStandards(r, utils.CountriesFile, countries)
+		checkFlags(r, utils.CountriesFile, utils.FlagsSVGDir, countries)
 	}
 
-	if err := ValidateFlags(); err != nil {
-		warnings = append(warnings, fmt.Sprintf("Flags warning:\n%v", err))
+	continents, err := utils.LoadContinents()
+	if err != nil {
+		r.addError("load-error", utils.ContinentsFile, 0, "", err)
+		loaded = false
+	} else {
+		checkContinentSet(r, utils.ContinentsFile, continents)
 	}
 
-	if len(warnings) > 0 {
-		fmt.Println(strings.Join(warnings, "\n\n"))
+	regions, err := utils.LoadRegions()
+	if err != nil {
+		r.addError("load-error", utils.RegionsFile, 0, "", err)
+		loaded = false
+	} else {
+		checkRegionSet(r, utils.RegionsFile, regions)
 	}
 
-	if len(allErrors) > 0 {
-		return fmt.Errorf("%s", strings.Join(allErrors, "\n\n"))
+	if loaded {
+		checkGeographyReferences(r, datasetGeographyFiles, countries, continents, regions)
+		checkContinentAggregates(r, utils.ContinentsFile, countries, continents, tolerance)
 	}
 
-	return nil
+	return r
 }
 
-func ValidateCountries() error {
-	countries, err := utils.LoadCountries()
-	if err != nil {
-		return err
-	}
-
+func checkCountrySet(r *Report, file string, countries []models.Country) {
 	slugs := make(map[string]bool)
 	isoAlpha2s := make(map[string]bool)
 	isoAlpha3s := make(map[string]bool)
-	var errors []string
 
 	for i, c := range countries {
 		if err := validateCountry(c); err != nil {
-			errors = append(errors, fmt.Sprintf("line %d (slug: %s): %v", i+1, c.Slug, err))
+			r.addError("invalid-country", file, i+1, c.Slug, err)
 			continue
 		}
 
 		if slugs[c.Slug] {
-			errors = append(errors, fmt.Sprintf("duplicate slug '%s' at line %d", c.Slug, i+1))
+			r.Add(Finding{Rule: "duplicate-slug", File: file, Line: i + 1, Slug: c.Slug, Path: "/slug", Message: fmt.Sprintf("duplicate slug '%s'", c.Slug)})
 		} else {
 			slugs[c.Slug] = true
 		}
 
 		if isoAlpha2s[c.ISOAlpha2] {
-			errors = append(errors, fmt.Sprintf("duplicate iso_alpha2 '%s' at line %d", c.ISOAlpha2, i+1))
+			r.Add(Finding{Rule: "duplicate-iso-alpha2", File: file, Line: i + 1, Slug: c.Slug, Path: "/iso_alpha2", Message: fmt.Sprintf("duplicate iso_alpha2 '%s'", c.ISOAlpha2)})
 		} else {
 			isoAlpha2s[c.ISOAlpha2] = true
 		}
 
 		if isoAlpha3s[c.ISOAlpha3] {
-			errors = append(errors, fmt.Sprintf("duplicate iso_alpha3 '%s' at line %d", c.ISOAlpha3, i+1))
+			r.Add(Finding{Rule: "duplicate-iso-alpha3", File: file, Line: i + 1, Slug: c.Slug, Path: "/iso_alpha3", Message: fmt.Sprintf("duplicate iso_alpha3 '%s'", c.ISOAlpha3)})
 		} else {
 			isoAlpha3s[c.ISOAlpha3] = true
 		}
 	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("validation errors:\n%s", strings.Join(errors, "\n"))
-	}
-
-	return nil
 }
 
 func validateCountry(c models.Country) error {
 	if c.Slug == "" {
-		return fmt.Errorf("slug is required")
+		return fieldErrorf("/slug", "slug is required")
 	}
 	if c.ISOAlpha2 == "" {
-		return fmt.Errorf("iso_alpha2 is required")
+		return fieldErrorf("/iso_alpha2", "iso_alpha2 is required")
 	}
 	if len(c.ISOAlpha2) != 2 {
-		return fmt.Errorf("iso_alpha2 must be 2 characters (got '%s')", c.ISOAlpha2)
+		return fieldErrorf("/iso_alpha2", "iso_alpha2 must be 2 characters (got '%s')", c.ISOAlpha2)
 	}
 	if c.ISOAlpha3 == "" {
-		return fmt.Errorf("iso_alpha3 is required")
+		return fieldErrorf("/iso_alpha3", "iso_alpha3 is required")
 	}
 	if len(c.ISOAlpha3) != 3 {
-		return fmt.Errorf("iso_alpha3 must be 3 characters (got '%s')", c.ISOAlpha3)
+		return fieldErrorf("/iso_alpha3", "iso_alpha3 must be 3 characters (got '%s')", c.ISOAlpha3)
 	}
 
 	requiredLangs := []string{"en", "fr", "es"}
 	for _, lang := range requiredLangs {
 		if c.Name[lang] == "" {
-			return fmt.Errorf("name.%s is required", lang)
+			return fieldErrorf("/name/"+lang, "name.%s is required", lang)
 		}
 		if c.OfficialName[lang] == "" {
-			return fmt.Errorf("official_name.%s is required", lang)
+			return fieldErrorf("/official_name/"+lang, "official_name.%s is required", lang)
 		}
 	}
 
 	if c.Continent == "" {
-		return fmt.Errorf("continent is required")
+		return fieldErrorf("/continent", "continent is required")
 	}
 
 	if c.Coordinates.Lat < -90 || c.Coordinates.Lat > 90 {
-		return fmt.Errorf("coordinates.lat must be between -90 and 90 (got %.6f)", c.Coordinates.Lat)
+		return fieldErrorf("/coordinates/lat", "coordinates.lat must be between -90 and 90 (got %.6f)", c.Coordinates.Lat)
 	}
 	if c.Coordinates.Lng < -180 || c.Coordinates.Lng > 180 {
-		return fmt.Errorf("coordinates.lng must be between -180 and 180 (got %.6f)", c.Coordinates.Lng)
+		return fieldErrorf("/coordinates/lng", "coordinates.lng must be between -180 and 180 (got %.6f)", c.Coordinates.Lng)
 	}
 
 	validDrivingSides := []string{"left", "right"}
-	if !contains(validDrivingSides, c.DrivingSide) {
-		return fmt.Errorf("driving_side must be 'left' or 'right' (got '%s')", c.DrivingSide)
+	if !utils.Contains(validDrivingSides, c.DrivingSide) {
+		return fieldErrorf("/driving_side", "driving_side must be 'left' or 'right' (got '%s')", c.DrivingSide)
 	}
 
 	return nil
 }
 
-func ValidateContinents() error {
-	continents, err := utils.LoadContinents()
-	if err != nil {
-		return err
-	}
-
+func checkContinentSet(r *Report, file string, continents []models.Continent) {
 	ids := make(map[string]bool)
-	var errors []string
 
 	for i, c := range continents {
 		if err := validateContinent(c); err != nil {
-			errors = append(errors, fmt.Sprintf("line %d (slug: %s): %v", i+1, c.Slug, err))
+			r.addError("invalid-continent", file, i+1, c.Slug, err)
 			continue
 		}
 
 		if ids[c.Slug] {
-			errors = append(errors, fmt.Sprintf("duplicate slug '%s' at line %d", c.Slug, i+1))
+			r.Add(Finding{Rule: "duplicate-slug", File: file, Line: i + 1, Slug: c.Slug, Path: "/slug", Message: fmt.Sprintf("duplicate slug '%s'", c.Slug)})
 		} else {
 			ids[c.Slug] = true
 		}
 	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("validation errors:\n%s", strings.Join(errors, "\n"))
-	}
-
-	return nil
 }
 
 func validateContinent(c models.Continent) error {
 	if c.Slug == "" {
-		return fmt.Errorf("slug is required")
+		return fieldErrorf("/slug", "slug is required")
 	}
 
 	requiredLangs := []string{"en", "fr", "es"}
 	for _, lang := range requiredLangs {
 		if c.Name[lang] == "" {
-			return fmt.Errorf("name.%s is required", lang)
+			return fieldErrorf("/name/"+lang, "name.%s is required", lang)
 		}
 	}
 
 	if len(c.Countries) == 0 {
-		return fmt.Errorf("countries list cannot be empty")
+		return fieldErrorf("/countries", "countries list cannot be empty")
 	}
 
 	return nil
 }
 
-func ValidateRegions() error {
-	regions, err := utils.LoadRegions()
-	if err != nil {
-		return err
-	}
-
+func checkRegionSet(r *Report, file string, regions []models.Region) {
 	ids := make(map[string]bool)
-	var errors []string
 
-	for i, r := range regions {
-		if err := validateRegion(r); err != nil {
-			errors = append(errors, fmt.Sprintf("line %d (slug: %s): %v", i+1, r.Slug, err))
+	for i, region := range regions {
+		if err := validateRegion(region); err != nil {
+			r.addError("invalid-region", file, i+1, region.Slug, err)
 			continue
 		}
 
-		if ids[r.Slug] {
-			errors = append(errors, fmt.Sprintf("duplicate slug '%s' at line %d", r.Slug, i+1))
+		if ids[region.Slug] {
+			r.Add(Finding{Rule: "duplicate-slug", File: file, Line: i + 1, Slug: region.Slug, Path: "/slug", Message: fmt.Sprintf("duplicate slug '%s'", region.Slug)})
 		} else {
-			ids[r.Slug] = true
+			ids[region.Slug] = true
 		}
 	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("validation errors:\n%s", strings.Join(errors, "\n"))
-	}
-
-	return nil
 }
 
 func validateRegion(r models.Region) error {
 	if r.Slug == "" {
-		return fmt.Errorf("slug is required")
+		return fieldErrorf("/slug", "slug is required")
 	}
 
 	requiredLangs := []string{"en", "fr", "es"}
 	for _, lang := range requiredLangs {
 		if r.Name[lang] == "" {
-			return fmt.Errorf("name.%s is required", lang)
+			return fieldErrorf("/name/"+lang, "name.%s is required", lang)
 		}
 	}
 
 	if r.Continent == "" {
-		return fmt.Errorf("continent is required")
+		return fieldErrorf("/continent", "continent is required")
 	}
 
 	if len(r.Countries) == 0 {
-		return fmt.Errorf("countries list cannot be empty")
+		return fieldErrorf("/countries", "countries list cannot be empty")
 	}
 
 	return nil
 }
 
-func ValidateFlags() error {
-	countries, err := utils.LoadCountries()
-	if err != nil {
-		return err
-	}
-
-	var errors []string
-
-	for _, c := range countries {
-		if c.Flag == "" {
-			continue
-		}
-
-		flagPath := filepath.Join(utils.FlagsSVGDir, c.Flag+".svg")
-		if _, err := os.Stat(flagPath); os.IsNotExist(err) {
-			errors = append(errors, fmt.Sprintf("missing flag file for %s: %s", c.Slug, flagPath))
-		}
-	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("missing flags:\n%s", strings.Join(errors, "\n"))
-	}
-
-	return nil
+func ValidateGeographySet(countries []models.Country, continents []models.Continent, regions []models.Region) error {
+	r := NewReport("validate-geography")
+	checkCountrySet(r, "", countries)
+	checkCountryStandards(r, "", countries)
+	checkContinentSet(r, "", continents)
+	checkRegionSet(r, "", regions)
+	checkGeographyReferences(r, geographyFiles{}, countries, continents, regions)
+	return r.Err()
 }
 
-func CheckGeographyDuplicates() string {
-	var results []string
+func CheckGeographyDuplicates() *Report {
+	r := NewReport("check-geography-duplicates")
 
 	countries, err := utils.LoadCountries()
 	if err != nil {
-		return fmt.Sprintf("✗ Error loading countries: %v", err)
+		r.addError("load-error", utils.CountriesFile, 0, "", err)
+		return r
 	}
 
 	slugs := make(map[string]int)
 	for i, c := range countries {
 		if firstLine, exists := slugs[c.Slug]; exists {
-			results = append(results, fmt.Sprintf("Duplicate country slug '%s' found at lines %d and %d", c.Slug, firstLine, i+1))
+			r.Add(Finding{Rule: "duplicate-slug", File: utils.CountriesFile, Line: i + 1, Slug: c.Slug, Path: "/slug", Message: fmt.Sprintf("duplicate country slug '%s': first occurrence line %d", c.Slug, firstLine)})
 		} else {
 			slugs[c.Slug] = i + 1
 		}
 	}
 
-	if len(results) == 0 {
-		return "✔ No duplicates detected in geography dataset"
-	}
-
-	return fmt.Sprintf("✗ Duplicates detected:\n%s", strings.Join(results, "\n"))
+	return r
 }
 
-func CheckGeographyTranslations() string {
-	var missing []string
+func CheckGeographyTranslations() *Report {
+	r := NewReport("check-geography-translations")
 	requiredLangs := []string{"en", "fr", "es"}
+	missing := func(file string, line int, slug, field, lang string) {
+		r.Add(Finding{Rule: "missing-translation", File: file, Line: line, Slug: slug, Path: "/" + field + "/" + lang, Message: fmt.Sprintf("missing %s translation for '%s'", lang, field)})
+	}
 
 	countries, err := utils.LoadCountries()
 	if err != nil {
-		return fmt.Sprintf("✗ Error loading countries: %v", err)
+		r.addError("load-error", utils.CountriesFile, 0, "", err)
+		return r
 	}
 
 	for i, c := range countries {
 		for _, lang := range requiredLangs {
 			if c.Name[lang] == "" {
-				missing = append(missing, fmt.Sprintf("Country line %d (slug: %s): missing %s translation for 'name'", i+1, c.Slug, lang))
+				missing(utils.CountriesFile, i+1, c.Slug, "name", lang)
 			}
 			if c.OfficialName[lang] == "" {
-				missing = append(missing, fmt.Sprintf("Country line %d (slug: %s): missing %s translation for 'official_name'", i+1, c.Slug, lang))
+				missing(utils.CountriesFile, i+1, c.Slug, "official_name", lang)
 			}
 		}
 	}
 
 	continents, err := utils.LoadContinents()
 	if err != nil {
-		return fmt.Sprintf("✗ Error loading continents: %v", err)
+		r.addError("load-error", utils.ContinentsFile, 0, "", err)
+		return r
 	}
 
 	for i, c := range continents {
 		for _, lang := range requiredLangs {
 			if c.Name[lang] == "" {
-				missing = append(missing, fmt.Sprintf("Continent line %d (slug: %s): missing %s translation for 'name'", i+1, c.Slug, lang))
+				missing(utils.ContinentsFile, i+1, c.Slug, "name", lang)
 			}
 		}
 	}
 
 	regions, err := utils.LoadRegions()
 	if err != nil {
-		return fmt.Sprintf("✗ Error loading regions: %v", err)
+		r.addError("load-error", utils.RegionsFile, 0, "", err)
+		return r
 	}
 
-	for i, r := range regions {
+	for i, region := range regions {
 		for _, lang := range requiredLangs {
-			if r.Name[lang] == "" {
-				missing = append(missing, fmt.Sprintf("Region line %d (slug: %s): missing %s translation for 'name'", i+1, r.Slug, lang))
+			if region.Name[lang] == "" {
+				missing(utils.RegionsFile, i+1, region.Slug, "name", lang)
+			}
+		}
+	}
+
+	return r
+}
+
+type geographyFiles struct {
+	countries  string
+	continents string
+	regions    string
+}
+
+var datasetGeographyFiles = geographyFiles{
+	countries:  utils.CountriesFile,
+	continents: utils.ContinentsFile,
+	regions:    utils.RegionsFile,
+}
+
+func checkGeographyReferences(r *Report, files geographyFiles, countries []models.Country, continents []models.Continent, regions []models.Region) {
+	countryIndex := make(map[string]int)
+	alpha3Index := make(map[string]int)
+	for i, c := range countries {
+		countryIndex[c.Slug] = i
+		alpha3Index[strings.ToLower(c.ISOAlpha3)] = i
+	}
+	continentIndex := make(map[string]int)
+	for i, c := range continents {
+		continentIndex[c.Slug] = i
+	}
+	regionIndex := make(map[string]int)
+	for i, region := range regions {
+		regionIndex[region.Slug] = i
+	}
+
+	for i, c := range countries {
+		if _, ok := continentIndex[c.Continent]; !ok && c.Continent != "" {
+			r.Add(Finding{Rule: "unknown-reference", File: files.countries, Line: i + 1, Slug: c.Slug, Path: "/continent", Message: fmt.Sprintf("continent '%s' does not exist", c.Continent)})
+		}
+		if j, ok := regionIndex[c.Region]; !ok {
+			r.Add(Finding{Rule: "unknown-reference", File: files.countries, Line: i + 1, Slug: c.Slug, Path: "/region", Message: fmt.Sprintf("region '%s' does not exist", c.Region)})
+		} else if regions[j].Continent != c.Continent {
+			r.Add(Finding{Rule: "inconsistent-reference", File: files.countries, Line: i + 1, Slug: c.Slug, Path: "/region", Message: fmt.Sprintf("region '%s' belongs to continent '%s', not '%s'", c.Region, regions[j].Continent, c.Continent)})
+		}
+
+		for k, code := range c.Neighbors {
+			path := fmt.Sprintf("/neighbors/%d", k)
+			j, ok := alpha3Index[strings.ToLower(code)]
+			if !ok {
+				r.Add(Finding{Rule: "unknown-reference", File: files.countries, Line: i + 1, Slug: c.Slug, Path: path, Message: fmt.Sprintf("neighbor '%s' is not a known iso_alpha3 code", code)})
+				continue
 			}
+			if !containsFold(countries[j].Neighbors, c.ISOAlpha3) {
+				r.Add(Finding{Rule: "one-sided-border", File: files.countries, Line: i + 1, Slug: c.Slug, Path: path, Message: fmt.Sprintf("border with '%s' is one-sided: %s does not list %s", code, countries[j].Slug, strings.ToLower(c.ISOAlpha3))})
+			}
+		}
+	}
+
+	for i, c := range continents {
+		checkGeographyMembers(r, files.continents, i+1, "continent", c.Slug, c.Countries, countries, countryIndex, func(c models.Country) string { return c.Continent })
+	}
+
+	for i, region := range regions {
+		if _, ok := continentIndex[region.Continent]; !ok && region.Continent != "" {
+			r.Add(Finding{Rule: "unknown-reference", File: files.regions, Line: i + 1, Slug: region.Slug, Path: "/continent", Message: fmt.Sprintf("continent '%s' does not exist", region.Continent)})
+		}
+		checkGeographyMembers(r, files.regions, i+1, "region", region.Slug, region.Countries, countries, countryIndex, func(c models.Country) string { return c.Region })
+	}
+}
+
+func checkGeographyMembers(r *Report, file string, line int, kind, slug string, members []string, countries []models.Country, countryIndex map[string]int, group func(models.Country) string) {
+	listed := make(map[string]bool)
+	for k, member := range members {
+		listed[member] = true
+		path := fmt.Sprintf("/countries/%d", k)
+		j, ok := countryIndex[member]
+		if !ok {
+			r.Add(Finding{Rule: "unknown-reference", File: file, Line: line, Slug: slug, Path: path, Message: fmt.Sprintf("country '%s' does not exist", member)})
+			continue
+		}
+		if actual := group(countries[j]); actual != slug {
+			r.Add(Finding{Rule: "inconsistent-reference", File: file, Line: line, Slug: slug, Path: path, Message: fmt.Sprintf("country '%s' belongs to %s '%s'", member, kind, actual)})
+		}
+	}
+
+	for _, c := range countries {
+		if group(c) == slug && !listed[c.Slug] {
+			r.Add(Finding{Rule: "inconsistent-reference", File: file, Line: line, Slug: slug, Path: "/countries", Message: fmt.Sprintf("country '%s' points to this %s but is not listed", c.Slug, kind)})
 		}
 	}
+}
 
-	if len(missing) == 0 {
-		return "✔ All geography translations present"
+func containsFold(slice []string, item string) bool {
+	for _, s := range slice {
+		if strings.EqualFold(s, item) {
+			return true
+		}
 	}
+	return false
+}
 
-	return fmt.Sprintf("✗ Missing translations:\n%s", strings.Join(missing, "\n"))
+// TranscontinentalCountries lie on two continents by the usual boundaries
+// but are listed in a single one, with their whole population and area.
+var TranscontinentalCountries = []string{"az", "eg", "ge", "id", "kz", "pa", "ru", "tr", "ye"}
+
+// ContinentTotals sums the population and area of the countries a continent
+// lists. It also returns the transcontinental countries among them: when
+// there are any, the sums are not the real totals of the continent.
+func ContinentTotals(continent models.Continent, countries []models.Country) (int64, float64, []string) {
+	bySlug := make(map[string]models.Country)
+	for _, c := range countries {
+		bySlug[c.Slug] = c
+	}
+
+	var population int64
+	var area float64
+	var transcontinental []string
+	for _, slug := range continent.Countries {
+		if c, ok := bySlug[slug]; ok {
+			population += c.Population
+			area += c.AreaKm2
+		}
+		if utils.Contains(TranscontinentalCountries, slug) {
+			transcontinental = append(transcontinental, slug)
+		}
+	}
+	return population, math.Round(area*100) / 100, transcontinental
+}
+
+func checkContinentAggregates(r *Report, file string, countries []models.Country, continents []models.Continent, tolerance float64) {
+	for i, c := range continents {
+		population, area, _ := ContinentTotals(c, countries)
+		if exceedsTolerance(float64(c.Population), float64(population), tolerance) {
+			r.Add(Finding{Rule: "aggregate-mismatch", Severity: SeverityWarning, File: file, Line: i + 1, Slug: c.Slug, Path: "/population", Message: fmt.Sprintf("population %d differs from the sum of its countries (%d) by more than %g%%", c.Population, population, tolerance*100)})
+		}
+		if exceedsTolerance(c.AreaKm2, area, tolerance) {
+			r.Add(Finding{Rule: "aggregate-mismatch", Severity: SeverityWarning, File: file, Line: i + 1, Slug: c.Slug, Path: "/area_km2", Message: fmt.Sprintf("area_km2 %s differs from the sum of its countries (%s) by more than %g%%", strconv.FormatFloat(c.AreaKm2, 'f', -1, 64), strconv.FormatFloat(area, 'f', -1, 64), tolerance*100)})
+		}
+	}
+}
+
+func exceedsTolerance(value, expected, tolerance float64) bool {
+	if expected == 0 {
+		return value != 0
+	}
+	return math.Abs(value-expected)/expected > tolerance
 }
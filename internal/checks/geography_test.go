@@ -0,0 +1,155 @@
+package checks
+
+import (
+	"reflect"
+	"testing"
+
+	"cultpedia/internal/models"
+)
+
+func createReferenceTestData() ([]models.Country, []models.Continent, []models.Region) {
+	countries := []models.Country{
+		{Slug: "fr", ISOAlpha3: "FRA", Continent: "europe", Region: "western_europe", Neighbors: []string{"bel", "deu"}},
+		{Slug: "be", ISOAlpha3: "BEL", Continent: "europe", Region: "western_europe", Neighbors: []string{"fra", "deu"}},
+		{Slug: "de", ISOAlpha3: "DEU", Continent: "europe", Region: "central_europe", Neighbors: []string{"fra", "bel"}},
+		{Slug: "jp", ISOAlpha3: "JPN", Continent: "asia", Region: "eastern_asia"},
+	}
+	continents := []models.Continent{
+		{Slug: "europe", Countries: []string{"be", "de", "fr"}},
+		{Slug: "asia", Countries: []string{"jp"}},
+	}
+	regions := []models.Region{
+		{Slug: "western_europe", Continent: "europe", Countries: []string{"be", "fr"}},
+		{Slug: "central_europe", Continent: "europe", Countries: []string{"de"}},
+		{Slug: "eastern_asia", Continent: "asia", Countries: []string{"jp"}},
+	}
+	return countries, continents, regions
+}
+
+func TestCheckGeographyReferences(t *testing.T) {
+	files := geographyFiles{countries: "countries.ndjson", continents: "continents.ndjson", regions: "regions.ndjson"}
+
+	countries, continents, regions := createReferenceTestData()
+	r := NewReport("validate-geography")
+	checkGeographyReferences(r, files, countries, continents, regions)
+	if len(r.Findings) != 0 {
+		t.Fatalf("consistent data should have no findings, got %+v", r.Findings)
+	}
+
+	tests := []struct {
+		name     string
+		change   func([]models.Country, []models.Continent, []models.Region)
+		expected []Finding
+	}{
+		{
+			name:   "unknown region",
+			change: func(c []models.Country, _ []models.Continent, _ []models.Region) { c[3].Region = "far_east" },
+			expected: []Finding{
+				{Rule: "unknown-reference", File: "countries.ndjson", Line: 4, Slug: "jp", Path: "/region"},
+				{Rule: "inconsistent-reference", File: "regions.ndjson", Line: 3, Slug: "eastern_asia", Path: "/countries/0"},
+			},
+		},
+		{
+			name:   "region on another continent",
+			change: func(_ []models.Country, _ []models.Continent, r []models.Region) { r[1].Continent = "asia" },
+			expected: []Finding{
+				{Rule: "inconsistent-reference", File: "countries.ndjson", Line: 3, Slug: "de", Path: "/region"},
+			},
+		},
+		{
+			name: "country missing from continent",
+			change: func(_ []models.Country, c []models.Continent, _ []models.Region) {
+				c[0].Countries = []string{"be", "fr", "xx"}
+			},
+			expected: []Finding{
+				{Rule: "unknown-reference", File: "continents.ndjson", Line: 1, Slug: "europe", Path: "/countries/2"},
+				{Rule: "inconsistent-reference", File: "continents.ndjson", Line: 1, Slug: "europe", Path: "/countries"},
+			},
+		},
+		{
+			name: "country listed in the wrong region",
+			change: func(_ []models.Country, _ []models.Continent, r []models.Region) {
+				r[0].Countries = append(r[0].Countries, "de")
+			},
+			expected: []Finding{
+				{Rule: "inconsistent-reference", File: "regions.ndjson", Line: 1, Slug: "western_europe", Path: "/countries/2"},
+			},
+		},
+		{
+			name: "one-sided and unknown neighbors",
+			change: func(c []models.Country, _ []models.Continent, _ []models.Region) {
+				c[0].Neighbors = []string{"bel", "deu", "JPN", "xyz"}
+			},
+			expected: []Finding{
+				{Rule: "one-sided-border", File: "countries.ndjson", Line: 1, Slug: "fr", Path: "/neighbors/2"},
+				{Rule: "unknown-reference", File: "countries.ndjson", Line: 1, Slug: "fr", Path: "/neighbors/3"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			countries, continents, regions := createReferenceTestData()
+			tt.change(countries, continents, regions)
+
+			r := NewReport("validate-geography")
+			checkGeographyReferences(r, files, countries, continents, regions)
+			if len(r.Findings) != len(tt.expected) {
+				t.Fatalf("expected %d findings, got %+v", len(tt.expected), r.Findings)
+			}
+			for i, expected := range tt.expected {
+				got := r.Findings[i]
+				got.Message, got.Severity = "", ""
+				if got != expected {
+					t.Errorf("finding %d = %+v, expected %+v", i, got, expected)
+				}
+			}
+		})
+	}
+	countries, continents, regions = createReferenceTestData()
+	countries[3].Region = "far_east"
+	r = NewReport("validate-geography")
+	checkGeographyReferences(r, files, countries, continents, regions)
+	if len(r.Findings) == 0 || r.Findings[0].Severity != SeverityError {
+		t.Errorf("an unknown region should be an error, got %+v", r.Findings)
+	}
+}
+
+func TestCheckContinentAggregates(t *testing.T) {
+	countries := []models.Country{
+		{Slug: "fr", Population: 68000000, AreaKm2: 551695},
+		{Slug: "be", Population: 11800000, AreaKm2: 30528.5},
+		{Slug: "jp", Population: 124000000, AreaKm2: 377930},
+	}
+	continents := []models.Continent{
+		{Slug: "europe", Countries: []string{"fr", "be"}, Population: 79900000, AreaKm2: 582223.5},
+		{Slug: "asia", Countries: []string{"jp"}, Population: 130000000, AreaKm2: 377000},
+	}
+
+	population, area, transcontinental := ContinentTotals(continents[0], countries)
+	if population != 79800000 || area != 582223.5 || transcontinental != nil {
+		t.Errorf("ContinentTotals() = %d, %v, %v", population, area, transcontinental)
+	}
+	if _, _, transcontinental := ContinentTotals(models.Continent{Countries: []string{"fr", "ru", "tr"}}, countries); !reflect.DeepEqual(transcontinental, []string{"ru", "tr"}) {
+		t.Errorf("ContinentTotals() transcontinental = %v, expected [ru tr]", transcontinental)
+	}
+
+	r := NewReport("validate-geography")
+	checkContinentAggregates(r, "continents.ndjson", countries, continents, DefaultAggregateTolerance)
+	if len(r.Findings) != 1 {
+		t.Fatalf("expected 1 finding, got %+v", r.Findings)
+	}
+	if f := r.Findings[0]; f.Slug != "asia" || f.Path != "/population" || f.Line != 2 || f.Rule != "aggregate-mismatch" || f.Severity != SeverityWarning {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+
+	r = NewReport("validate-geography")
+	checkContinentAggregates(r, "continents.ndjson", countries, continents, 0)
+	if len(r.Findings) != 3 {
+		t.Errorf("zero tolerance should report every difference, got %+v", r.Findings)
+	}
+
+	if !exceedsTolerance(1000, 0, 0.5) || exceedsTolerance(0, 0, 0) {
+		t.Error("exceedsTolerance() should only accept zero when the expected total is zero")
+	}
+}
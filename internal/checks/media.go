@@ -0,0 +1,161 @@
+package checks
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+const (
+	MaxImageSize = 512 * 1024
+	MaxAudioSize = 2 * 1024 * 1024
+)
+
+// SVG is left out on purpose: unlike flags, question media is not sanitized.
+var mediaTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".webp": "image/webp",
+	".mp3":  "audio/mpeg",
+	".ogg":  "audio/ogg",
+}
+
+var mediaLicenses = []string{"CC0-1.0", "CC-BY-3.0", "CC-BY-4.0", "CC-BY-SA-3.0", "CC-BY-SA-4.0", "public-domain"}
+
+var mediaFilePattern = regexp.MustCompile(`^[a-z0-9]+([-_][a-z0-9]+)*\.[a-z0-9]+$`)
+
+// MediaContentType returns the MIME type of a media file name, and false for
+// names that are not a lowercase file name with an allowed extension.
+func MediaContentType(name string) (string, bool) {
+	if !mediaFilePattern.MatchString(name) {
+		return "", false
+	}
+	contentType, ok := mediaTypes[filepath.Ext(name)]
+	return contentType, ok
+}
+
+func validateMedia(m models.Media) error {
+	if _, ok := MediaContentType(m.File); !ok {
+		extensions := make([]string, 0, len(mediaTypes))
+		for ext := range mediaTypes {
+			extensions = append(extensions, ext)
+		}
+		sort.Strings(extensions)
+		return fieldErrorf("/media/file", "media file must be a lowercase file name ending in %s (got '%s')", strings.Join(extensions, ", "), m.File)
+	}
+	if !utils.Contains(mediaLicenses, m.License) {
+		return fieldErrorf("/media/license", "media license must be one of: %s (got '%s')", strings.Join(mediaLicenses, ", "), m.License)
+	}
+	if strings.TrimSpace(m.Attribution) == "" {
+		return fieldErrorf("/media/attribution", "media attribution is required")
+	}
+	if m.SourceURL != "" {
+		if err := validateURL(m.SourceURL); err != nil {
+			return fieldErrorf("/media/source_url", "invalid media source URL (%s): %v", m.SourceURL, err)
+		}
+	}
+	return nil
+}
+
+// ValidateMediaFile checks that the media file at path exists, starts like
+// the format its extension announces and fits the size limit of its kind.
+func ValidateMediaFile(path string) error {
+	_, err := inspectMedia(path)
+	return err
+}
+
+func checkMedia(r *Report, file, dir string, questions []models.Question) {
+	referenced := make(map[string]bool)
+	for i, q := range questions {
+		if q.Media == nil {
+			continue
+		}
+		if _, ok := MediaContentType(q.Media.File); !ok {
+			continue
+		}
+		referenced[q.Media.File] = true
+
+		mediaPath := filepath.Join(dir, q.Media.File)
+		rule, err := inspectMedia(mediaPath)
+		switch {
+		case err == nil:
+		case rule == "missing-media":
+			r.Add(Finding{Rule: rule, File: file, Line: i + 1, Slug: q.Slug, Path: "/media/file", Message: err.Error()})
+		default:
+			r.Add(Finding{Rule: rule, File: mediaPath, Slug: q.Slug, Message: err.Error()})
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var orphans []string
+	for _, entry := range entries {
+		if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && !referenced[entry.Name()] {
+			orphans = append(orphans, entry.Name())
+		}
+	}
+	sort.Strings(orphans)
+	for _, name := range orphans {
+		r.Add(Finding{Rule: "orphan-media", Severity: SeverityWarning, File: filepath.Join(dir, name), Message: "no question references this media file"})
+	}
+}
+
+// inspectMedia returns the rule broken by the media file at path along with
+// the error, or an empty rule and a nil error for a valid file.
+func inspectMedia(path string) (string, error) {
+	contentType, ok := MediaContentType(filepath.Base(path))
+	if !ok {
+		return "invalid-media", fmt.Errorf("%s is not an allowed media file name", filepath.Base(path))
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "missing-media", fmt.Errorf("missing media file %s", path)
+	}
+	if err != nil {
+		return "load-error", err
+	}
+
+	if sniffed := sniffMedia(data); sniffed != contentType {
+		if sniffed == "" {
+			sniffed = "an unknown format"
+		}
+		return "invalid-media", fmt.Errorf("content is %s, the extension says %s", sniffed, contentType)
+	}
+	limit := MaxImageSize
+	if strings.HasPrefix(contentType, "audio/") {
+		limit = MaxAudioSize
+	}
+	if len(data) > limit {
+		return "media-size", fmt.Errorf("media is %d bytes, the limit is %d", len(data), limit)
+	}
+	return "", nil
+}
+
+// sniffMedia recognizes the allowed formats from their magic numbers.
+// http.DetectContentType is not enough: it only knows MP3 files with an ID3
+// tag and reports Ogg as application/ogg.
+func sniffMedia(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "image/png"
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return "image/jpeg"
+	case len(data) >= 12 && bytes.HasPrefix(data, []byte("RIFF")) && string(data[8:12]) == "WEBP":
+		return "image/webp"
+	case bytes.HasPrefix(data, []byte("ID3")), len(data) >= 2 && data[0] == 0xff && data[1]&0xe0 == 0xe0:
+		return "audio/mpeg"
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return "audio/ogg"
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cultpedia/internal/models"
+)
+
+const testJPEG = "\xff\xd8\xff\xe0\x00\x10JFIF\x00"
+
+func TestValidateMedia(t *testing.T) {
+	withMedia := func(m models.Media) models.Question {
+		q := createValidQuestion()
+		q.Media = &m
+		return q
+	}
+
+	valid := models.Media{File: "mona-lisa.jpg", License: "public-domain", Attribution: "Leonardo da Vinci", SourceURL: "https://commons.wikimedia.org/"}
+	if err := validateQuestion(withMedia(valid)); err != nil {
+		t.Errorf("validateQuestion() returned unexpected error: %v", err)
+	}
+
+	invalid := map[string]func(m *models.Media){
+		"missing file":        func(m *models.Media) { m.File = "" },
+		"path":                func(m *models.Media) { m.File = "../questions.ndjson" },
+		"uppercase":           func(m *models.Media) { m.File = "Mona-Lisa.JPG" },
+		"svg":                 func(m *models.Media) { m.File = "mona-lisa.svg" },
+		"unknown license":     func(m *models.Media) { m.License = "all-rights-reserved" },
+		"missing attribution": func(m *models.Media) { m.Attribution = " " },
+		"invalid source":      func(m *models.Media) { m.SourceURL = "ftp://example.com/a.jpg" },
+	}
+	for name, modify := range invalid {
+		t.Run(name, func(t *testing.T) {
+			m := valid
+			modify(&m)
+			if err := validateQuestion(withMedia(m)); err == nil {
+				t.Error("validateQuestion() should return error")
+			}
+		})
+	}
+}
+
+func TestCheckMedia(t *testing.T) {
+	dir := t.TempDir()
+	writeMedia := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeMedia("mona-lisa.jpg", testJPEG)
+	writeMedia("anthem.mp3", "ID3"+strings.Repeat("x", MaxAudioSize))
+	writeMedia("fake.png", testJPEG)
+	writeMedia("unused.ogg", "OggS")
+	writeMedia(".gitkeep", "")
+
+	media := func(slug, file string) models.Question {
+		return models.Question{Slug: slug, Media: &models.Media{File: file}}
+	}
+	questions := []models.Question{
+		media("q1", "mona-lisa.jpg"),
+		media("q2", "anthem.mp3"),
+		{Slug: "q3"},
+		media("q4", "missing.webp"),
+		media("q5", "fake.png"),
+		media("q6", "mona-lisa.jpg"),
+	}
+
+	r := NewReport("validate")
+	checkMedia(r, "questions.ndjson", dir, questions)
+
+	expected := []Finding{
+		{Rule: "media-size", Severity: SeverityError, File: filepath.Join(dir, "anthem.mp3"), Slug: "q2"},
+		{Rule: "missing-media", Severity: SeverityError, File: "questions.ndjson", Line: 4, Slug: "q4", Path: "/media/file"},
+		{Rule: "invalid-media", Severity: SeverityError, File: filepath.Join(dir, "fake.png"), Slug: "q5"},
+		{Rule: "orphan-media", Severity: SeverityWarning, File: filepath.Join(dir, "unused.ogg")},
+	}
+	if len(r.Findings) != len(expected) {
+		t.Fatalf("expected %d findings, got %+v", len(expected), r.Findings)
+	}
+	for i, e := range expected {
+		got := r.Findings[i]
+		got.Message = ""
+		if got != e {
+			t.Errorf("finding %d = %+v, expected %+v", i, got, e)
+		}
+	}
+
+	if err := ValidateMediaFile(filepath.Join(dir, "mona-lisa.jpg")); err != nil {
+		t.Errorf("ValidateMediaFile() returned unexpected error: %v", err)
+	}
+}
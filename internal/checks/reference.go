@@ -0,0 +1,148 @@
+package checks
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"cultpedia/internal/models"
+)
+
+// Reference tables are generated from the Debian iso-codes package (4.15).
+// Kosovo has no ISO code, XK/UNK/926 are the values used by REST Countries.
+
+//go:embed reference/iso3166-1.csv
+var iso3166CSV string
+
+//go:embed reference/iso4217.csv
+var iso4217CSV string
+
+//go:embed reference/iso639.csv
+var iso639CSV string
+
+type isoCountry struct {
+	alpha2  string
+	alpha3  string
+	numeric string
+}
+
+type coordinateBounds struct {
+	minLat, maxLat float64
+	minLng, maxLng float64
+}
+
+var (
+	referenceOnce sync.Once
+	iso3166       map[string]isoCountry
+	iso4217       map[string]bool
+	iso639        map[string]bool
+)
+
+var tldExceptions = map[string]string{
+	"GB": ".uk",
+	"UM": ".us",
+}
+
+var phoneCodePattern = regexp.MustCompile(`^\+[1-9][0-9]{0,2}( [0-9]{1,4})?$`)
+
+// Boxes are deliberately loose: they catch swapped or zeroed coordinates, not
+// borders. minLng > maxLng means the box crosses the antimeridian.
+var continentBounds = map[string]coordinateBounds{
+	"africa":     {minLat: -50, maxLat: 38, minLng: -26, maxLng: 75},
+	"americas":   {minLat: -60, maxLat: 84, minLng: 150, maxLng: -10},
+	"antarctica": {minLat: -90, maxLat: -45, minLng: -180, maxLng: 180},
+	"asia":       {minLat: -12, maxLat: 82, minLng: 25, maxLng: 180},
+	"europe":     {minLat: 34, maxLat: 82, minLng: -32, maxLng: 180},
+	"oceania":    {minLat: -56, maxLat: 30, minLng: 90, maxLng: -120},
+}
+
+func loadReferenceTables() {
+	referenceOnce.Do(func() {
+		iso3166 = make(map[string]isoCountry)
+		for _, record := range parseReferenceCSV(iso3166CSV) {
+			iso3166[record[0]] = isoCountry{alpha2: record[0], alpha3: record[1], numeric: record[2]}
+		}
+
+		iso4217 = make(map[string]bool)
+		for _, record := range parseReferenceCSV(iso4217CSV) {
+			iso4217[record[0]] = true
+		}
+
+		iso639 = make(map[string]bool)
+		for _, record := range parseReferenceCSV(iso639CSV) {
+			for _, code := range record[:3] {
+				if code != "" {
+					iso639[code] = true
+				}
+			}
+		}
+	})
+}
+
+func parseReferenceCSV(data string) [][]string {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded reference table: %v", err))
+	}
+	return records[1:]
+}
+
+func (b coordinateBounds) contains(c models.Coordinates) bool {
+	if c.Lat < b.minLat || c.Lat > b.maxLat {
+		return false
+	}
+	if b.minLng <= b.maxLng {
+		return c.Lng >= b.minLng && c.Lng <= b.maxLng
+	}
+	return c.Lng >= b.minLng || c.Lng <= b.maxLng
+}
+
+func checkCountryStandards(r *Report, file string, countries []models.Country) {
+	loadReferenceTables()
+
+	for i, c := range countries {
+		add := func(rule, path, format string, args ...interface{}) {
+			r.Add(Finding{Rule: rule, File: file, Line: i + 1, Slug: c.Slug, Path: path, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if ref, ok := iso3166[c.ISOAlpha2]; !ok {
+			add("iso-3166", "/iso_alpha2", "iso_alpha2 '%s' is not an ISO 3166-1 code", c.ISOAlpha2)
+		} else {
+			if c.ISOAlpha3 != ref.alpha3 {
+				add("iso-3166", "/iso_alpha3", "iso_alpha3 '%s' does not match iso_alpha2 '%s' (expected '%s')", c.ISOAlpha3, c.ISOAlpha2, ref.alpha3)
+			}
+			if c.ISONumerics != ref.numeric {
+				add("iso-3166", "/iso_numeric", "iso_numeric '%s' does not match iso_alpha2 '%s' (expected '%s')", c.ISONumerics, c.ISOAlpha2, ref.numeric)
+			}
+
+			expectedTLD := "." + strings.ToLower(c.ISOAlpha2)
+			if exception, ok := tldExceptions[c.ISOAlpha2]; ok {
+				expectedTLD = exception
+			}
+			if c.TLD != "" && c.TLD != expectedTLD {
+				add("tld", "/tld", "tld '%s' does not match iso_alpha2 '%s' (expected '%s')", c.TLD, c.ISOAlpha2, expectedTLD)
+			}
+		}
+
+		if c.Currency.Code != "" && !iso4217[c.Currency.Code] {
+			add("iso-4217", "/currency/code", "currency code '%s' is not an ISO 4217 code", c.Currency.Code)
+		}
+
+		for k, lang := range c.Languages {
+			if !iso639[lang] {
+				add("iso-639", fmt.Sprintf("/languages/%d", k), "language '%s' is not an ISO 639 code", lang)
+			}
+		}
+
+		if !phoneCodePattern.MatchString(c.PhoneCode) {
+			add("phone-code", "/phone_code", "phone_code '%s' must be an E.164 country code like '+33' or '+1 340'", c.PhoneCode)
+		}
+
+		if bounds, ok := continentBounds[c.Continent]; ok && !bounds.contains(c.Coordinates) {
+			add("coordinates", "/coordinates", "coordinates (%g, %g) are outside %s", c.Coordinates.Lat, c.Coordinates.Lng, c.Continent)
+		}
+	}
+}
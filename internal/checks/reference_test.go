@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"testing"
+
+	"cultpedia/internal/models"
+)
+
+func createStandardsTestCountry() models.Country {
+	return models.Country{
+		Slug:        "fr",
+		ISOAlpha2:   "FR",
+		ISOAlpha3:   "FRA",
+		ISONumerics: "250",
+		Continent:   "europe",
+		Coordinates: models.Coordinates{Lat: 46, Lng: 2},
+		Currency:    models.Currency{Code: "EUR"},
+		Languages:   []string{"fr", "fra"},
+		TLD:         ".fr",
+		PhoneCode:   "+33",
+	}
+}
+
+func TestCheckCountryStandards(t *testing.T) {
+	r := NewReport("validate-geography")
+	checkCountryStandards(r, "countries.ndjson", []models.Country{createStandardsTestCountry()})
+	if len(r.Findings) != 0 {
+		t.Fatalf("valid country should have no findings, got %+v", r.Findings)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*models.Country)
+		rule   string
+		path   string
+	}{
+		{"unknown alpha2", func(c *models.Country) { c.ISOAlpha2 = "QQ" }, "iso-3166", "/iso_alpha2"},
+		{"mismatched alpha3", func(c *models.Country) { c.ISOAlpha3 = "FRC" }, "iso-3166", "/iso_alpha3"},
+		{"mismatched numeric", func(c *models.Country) { c.ISONumerics = "251" }, "iso-3166", "/iso_numeric"},
+		{"retired currency", func(c *models.Country) { c.Currency.Code = "FRF" }, "iso-4217", "/currency/code"},
+		{"unknown language", func(c *models.Country) { c.Languages = []string{"fr", "xx"} }, "iso-639", "/languages/1"},
+		{"tld", func(c *models.Country) { c.TLD = ".fx" }, "tld", "/tld"},
+		{"phone code without plus", func(c *models.Country) { c.PhoneCode = "33" }, "phone-code", "/phone_code"},
+		{"phone code too long", func(c *models.Country) { c.PhoneCode = "+3312" }, "phone-code", "/phone_code"},
+		{"swapped coordinates", func(c *models.Country) { c.Coordinates = models.Coordinates{Lat: 2, Lng: 46} }, "coordinates", "/coordinates"},
+		{"wrong continent", func(c *models.Country) { c.Continent = "oceania" }, "coordinates", "/coordinates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := createStandardsTestCountry()
+			tt.change(&c)
+
+			r := NewReport("validate-geography")
+			checkCountryStandards(r, "countries.ndjson", []models.Country{c})
+			if len(r.Findings) != 1 {
+				t.Fatalf("expected 1 finding, got %+v", r.Findings)
+			}
+			if r.Findings[0].Rule != tt.rule || r.Findings[0].Path != tt.path {
+				t.Errorf("got %s at %s, expected %s at %s", r.Findings[0].Rule, r.Findings[0].Path, tt.rule, tt.path)
+			}
+		})
+	}
+}
+
+func TestCountryStandardsExceptions(t *testing.T) {
+	gb := createStandardsTestCountry()
+	gb.Slug, gb.ISOAlpha2, gb.ISOAlpha3, gb.ISONumerics, gb.TLD, gb.PhoneCode = "gb", "GB", "GBR", "826", ".uk", "+44"
+	gb.Coordinates = models.Coordinates{Lat: 54, Lng: -2}
+
+	vi := createStandardsTestCountry()
+	vi.Slug, vi.ISOAlpha2, vi.ISOAlpha3, vi.ISONumerics, vi.TLD, vi.PhoneCode = "vi", "VI", "VIR", "850", ".vi", "+1 340"
+	vi.Continent, vi.Coordinates = "americas", models.Coordinates{Lat: 18.34, Lng: -64.93}
+
+	fj := createStandardsTestCountry()
+	fj.Slug, fj.ISOAlpha2, fj.ISOAlpha3, fj.ISONumerics, fj.TLD, fj.PhoneCode = "fj", "FJ", "FJI", "242", ".fj", "+679"
+	fj.Continent, fj.Coordinates = "oceania", models.Coordinates{Lat: -18, Lng: 175}
+
+	ws := fj
+	ws.Slug, ws.ISOAlpha2, ws.ISOAlpha3, ws.ISONumerics, ws.TLD, ws.PhoneCode = "ws", "WS", "WSM", "882", ".ws", "+685"
+	ws.Coordinates = models.Coordinates{Lat: -13.58, Lng: -172.33}
+
+	aq := createStandardsTestCountry()
+	aq.Slug, aq.ISOAlpha2, aq.ISOAlpha3, aq.ISONumerics, aq.TLD, aq.PhoneCode = "aq", "AQ", "ATA", "010", ".aq", "+672"
+	aq.Continent, aq.Coordinates, aq.Currency = "antarctica", models.Coordinates{Lat: -74.65, Lng: 4.48}, models.Currency{}
+
+	r := NewReport("validate-geography")
+	checkCountryStandards(r, "countries.ndjson", []models.Country{gb, vi, fj, ws, aq})
+	if len(r.Findings) != 0 {
+		t.Errorf("expected no findings, got %+v", r.Findings)
+	}
+}
@@ -0,0 +1,382 @@
+package checks
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatSARIF = "sarif"
+)
+
+var ReportFormats = []string{FormatText, FormatJSON, FormatJUnit, FormatSARIF}
+
+var ruleDescriptions = map[string]string{
+	"load-error":             "Dataset or schema file cannot be read",
+	"schema":                 "Value does not match the JSON schema",
+	"invalid-question":       "Question does not follow the dataset rules",
+	"invalid-country":        "Country does not follow the dataset rules",
+	"invalid-continent":      "Continent does not follow the dataset rules",
+	"invalid-region":         "Region does not follow the dataset rules",
+	"duplicate-slug":         "Slug is used more than once",
+	"duplicate-iso-alpha2":   "ISO alpha-2 code is used more than once",
+	"duplicate-iso-alpha3":   "ISO alpha-3 code is used more than once",
+	"missing-translation":    "Required translation is missing",
+	"empty-translation":      "Translated text is empty",
+	"template-text":          "Text was left as in the new question template",
+	"untranslated-text":      "Text is identical in several languages",
+	"question-punctuation":   "Stem does not use the question punctuation of its language",
+	"explanation-answer":     "Explanation does not mention the correct answer",
+	"missing-flag":           "Country flag file is missing",
+	"invalid-flag":           "Flag is not a well-formed SVG with a viewBox",
+	"unsafe-flag":            "Flag contains scripts, event handlers or external references",
+	"flag-size":              "Flag file is larger than the size budget",
+	"orphan-flag":            "Flag file is not referenced by any country",
+	"missing-media":          "Question media file is missing",
+	"invalid-media":          "Media file content does not match its extension",
+	"media-size":             "Media file is larger than the size budget",
+	"orphan-media":           "Media file is not referenced by any question",
+	"similar-question":       "Question is very similar to another question",
+	"unknown-reference":      "Referenced continent, region or country does not exist",
+	"inconsistent-reference": "Countries, regions and continents disagree with each other",
+	"one-sided-border":       "Neighbor does not list the country back",
+	"aggregate-mismatch":     "Continent total differs from the sum of its countries",
+	"iso-3166":               "Country codes are not a consistent ISO 3166-1 triple",
+	"iso-4217":               "Currency code is not an ISO 4217 code",
+	"iso-639":                "Language is not an ISO 639 code",
+	"tld":                    "Top-level domain does not match the country code",
+	"phone-code":             "Phone code is not an E.164 country code",
+	"coordinates":            "Coordinates are outside the declared continent",
+	"checksum-mismatch":      "File does not match the checksum in the manifest",
+	"count-mismatch":         "Manifest count differs from the number of records",
+	"missing-file":           "File listed in the manifest cannot be found",
+	"invalid-manifest":       "Manifest lists a file outside the dataset",
+	"invalid-signature":      "Manifest signature is missing or does not match the public key",
+}
+
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Slug     string   `json:"slug,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Related  string   `json:"related,omitempty"`
+	Score    float64  `json:"score,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	var location string
+	switch {
+	case f.File != "" && f.Line > 0:
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	case f.File != "":
+		location = f.File
+	case f.Line > 0:
+		location = fmt.Sprintf("line %d", f.Line)
+	}
+	if f.Slug != "" {
+		location = strings.TrimSpace(fmt.Sprintf("%s (slug: %s)", location, f.Slug))
+	}
+
+	var parts []string
+	for _, part := range []string{location, f.Path, f.Message} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	s := strings.Join(parts, ": ")
+	if f.Severity == SeverityWarning {
+		s = "warning: " + s
+	}
+	return s
+}
+
+type Report struct {
+	Name     string    `json:"name"`
+	Findings []Finding `json:"findings"`
+}
+
+func NewReport(name string) *Report {
+	return &Report{Name: name, Findings: []Finding{}}
+}
+
+func (r *Report) Add(f Finding) {
+	if f.Severity == "" {
+		f.Severity = SeverityError
+	}
+	r.Findings = append(r.Findings, f)
+}
+
+func (r *Report) addError(rule, file string, line int, slug string, err error) {
+	f := Finding{Rule: rule, File: file, Line: line, Slug: slug, Message: err.Error()}
+	var fieldErr *fieldError
+	if errors.As(err, &fieldErr) {
+		f.Path = fieldErr.path
+	}
+	r.Add(f)
+}
+
+func (r *Report) Count(severity Severity) int {
+	count := 0
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *Report) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+func (r *Report) Err() error {
+	var lines []string
+	for _, f := range r.Findings {
+		if f.Severity == SeverityError {
+			lines = append(lines, f.String())
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(lines, "\n"))
+}
+
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatText:
+		return r.writeText(w)
+	case FormatJSON:
+		return r.writeJSON(w)
+	case FormatJUnit:
+		return r.writeJUnit(w)
+	case FormatSARIF:
+		return r.writeSARIF(w)
+	}
+	return fmt.Errorf("unknown report format '%s' (expected one of: %s)", format, strings.Join(ReportFormats, ", "))
+}
+
+func (r *Report) writeText(w io.Writer) error {
+	for _, f := range r.Findings {
+		if _, err := fmt.Fprintln(w, f.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Report) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		*Report
+		Errors   int `json:"errors"`
+		Warnings int `json:"warnings"`
+	}{r, r.Count(SeverityError), r.Count(SeverityWarning)})
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (r *Report) writeJUnit(w io.Writer) error {
+	suite := junitTestSuite{Name: r.Name}
+	for _, f := range r.Findings {
+		name := f.Rule
+		if f.Slug != "" {
+			name += " " + f.Slug
+		}
+		if f.Line > 0 {
+			name += fmt.Sprintf(" (line %d)", f.Line)
+		}
+		testCase := junitTestCase{Name: name, ClassName: f.File}
+		if testCase.ClassName == "" {
+			testCase.ClassName = r.Name
+		}
+		if f.Severity == SeverityError {
+			testCase.Failure = &junitFailure{Message: f.Message, Type: f.Rule, Text: f.String()}
+			suite.Failures++
+		} else {
+			testCase.SystemOut = f.String()
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	if len(suite.Cases) == 0 {
+		suite.Cases = append(suite.Cases, junitTestCase{Name: r.Name, ClassName: r.Name})
+	}
+	suite.Tests = len(suite.Cases)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func (r *Report) writeSARIF(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "cultpedia",
+			InformationURI: "https://github.com/Culturae-org/cultpedia",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	rules := make(map[string]bool)
+	for _, f := range r.Findings {
+		rules[f.Rule] = true
+
+		result := sarifResult{RuleID: f.Rule, Level: string(f.Severity), Message: sarifMessage{Text: f.Message}}
+		if f.File != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: f.File}}}
+			if f.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		for key, value := range map[string]string{"slug": f.Slug, "path": f.Path, "related": f.Related} {
+			if value == "" {
+				continue
+			}
+			if result.Properties == nil {
+				result.Properties = make(map[string]string)
+			}
+			result.Properties[key] = value
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		description := ruleDescriptions[id]
+		if description == "" {
+			description = id
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: description}})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+type fieldError struct {
+	path    string
+	message string
+}
+
+func (e *fieldError) Error() string {
+	return e.message
+}
+
+func fieldErrorf(path, format string, args ...interface{}) error {
+	return &fieldError{path: path, message: fmt.Sprintf(format, args...)}
+}
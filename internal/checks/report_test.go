@@ -0,0 +1,147 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"cultpedia/internal/models"
+)
+
+func createTestReport() *Report {
+	q := createValidQuestion()
+	invalid := createValidQuestion()
+	invalid.Slug = "invalid-question"
+	delete(invalid.Answers[1].I18n, "fr")
+
+	r := NewReport("validate")
+	checkQuestionSet(r, "questions.ndjson", []models.Question{q, invalid, q})
+	r.Add(Finding{Rule: "missing-flag", Severity: SeverityWarning, File: "countries.ndjson", Line: 4, Slug: "fr", Message: "missing flag file"})
+	return r
+}
+
+func TestReportFindings(t *testing.T) {
+	r := createTestReport()
+
+	expected := []Finding{
+		{Rule: "invalid-question", Severity: SeverityError, File: "questions.ndjson", Line: 2, Slug: "invalid-question", Path: "/answers/1/i18n/fr", Message: "missing fr translation in answer b"},
+		{Rule: "duplicate-slug", Severity: SeverityError, File: "questions.ndjson", Line: 3, Slug: "test-question-slug", Path: "/slug", Message: "duplicate detected for slug 'test-question-slug'"},
+	}
+	for i, f := range expected {
+		if r.Findings[i] != f {
+			t.Errorf("finding %d = %+v, expected %+v", i, r.Findings[i], f)
+		}
+	}
+
+	if r.Count(SeverityError) != 2 || r.Count(SeverityWarning) != 1 || !r.HasErrors() {
+		t.Errorf("unexpected counts: %d errors, %d warnings", r.Count(SeverityError), r.Count(SeverityWarning))
+	}
+
+	err := r.Err()
+	if err == nil || strings.Contains(err.Error(), "missing flag") {
+		t.Errorf("Err() should only contain errors, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "questions.ndjson:2 (slug: invalid-question): /answers/1/i18n/fr: missing fr translation") {
+		t.Errorf("unexpected error text: %v", err)
+	}
+
+	warnings := NewReport("validate-geography")
+	warnings.Add(Finding{Rule: "missing-flag", Severity: SeverityWarning, Message: "missing flag file"})
+	if warnings.HasErrors() || warnings.Err() != nil {
+		t.Error("warnings should not fail a report")
+	}
+}
+
+func TestReportFormats(t *testing.T) {
+	r := createTestReport()
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := r.Write(&buf, FormatText); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 3 || lines[2] != "warning: countries.ndjson:4 (slug: fr): missing flag file" {
+			t.Errorf("unexpected text output:\n%s", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := r.Write(&buf, FormatJSON); err != nil {
+			t.Fatal(err)
+		}
+		var decoded struct {
+			Name     string    `json:"name"`
+			Errors   int       `json:"errors"`
+			Warnings int       `json:"warnings"`
+			Findings []Finding `json:"findings"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if decoded.Name != "validate" || decoded.Errors != 2 || decoded.Warnings != 1 || len(decoded.Findings) != 3 {
+			t.Errorf("unexpected JSON report: %+v", decoded)
+		}
+		if decoded.Findings[1] != r.Findings[1] {
+			t.Errorf("finding = %+v, expected %+v", decoded.Findings[1], r.Findings[1])
+		}
+	})
+
+	t.Run("junit", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := r.Write(&buf, FormatJUnit); err != nil {
+			t.Fatal(err)
+		}
+		var decoded junitTestSuites
+		if err := xml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid XML: %v", err)
+		}
+		suite := decoded.Suites[0]
+		if suite.Tests != 3 || suite.Failures != 2 || suite.Cases[0].Failure == nil || suite.Cases[2].Failure != nil {
+			t.Errorf("unexpected suite: %+v", suite)
+		}
+		if suite.Cases[1].ClassName != "questions.ndjson" || suite.Cases[1].Failure.Type != "duplicate-slug" {
+			t.Errorf("unexpected test case: %+v", suite.Cases[1])
+		}
+
+		buf.Reset()
+		if err := NewReport("validate").Write(&buf, FormatJUnit); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), `tests="1" failures="0"`) {
+			t.Errorf("empty report should contain one passing test:\n%s", buf.String())
+		}
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := r.Write(&buf, FormatSARIF); err != nil {
+			t.Fatal(err)
+		}
+		var decoded sarifLog
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid SARIF: %v", err)
+		}
+		if decoded.Version != "2.1.0" || len(decoded.Runs) != 1 {
+			t.Fatalf("unexpected SARIF log: %+v", decoded)
+		}
+		run := decoded.Runs[0]
+		if len(run.Tool.Driver.Rules) != 3 || run.Tool.Driver.Rules[0].ID != "duplicate-slug" {
+			t.Errorf("unexpected rules: %+v", run.Tool.Driver.Rules)
+		}
+		result := run.Results[0]
+		if result.Level != "error" || result.Locations[0].PhysicalLocation.Region.StartLine != 2 || result.Properties["path"] != "/answers/1/i18n/fr" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if run.Results[2].Level != "warning" {
+			t.Errorf("warning level = %s", run.Results[2].Level)
+		}
+	})
+
+	if err := r.Write(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("unknown format should return an error")
+	}
+}
@@ -0,0 +1,750 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cultpedia/internal/utils"
+)
+
+type Schema struct {
+	root     map[string]interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+type SchemaError struct {
+	File    string
+	Line    int
+	Pointer string
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	if e.File != "" {
+		return fmt.Sprintf("%s:%d: %s: %s", e.File, e.Line, pointer, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", pointer, e.Message)
+}
+
+type schemaTarget struct {
+	schema string
+	file   string
+	ndjson bool
+}
+
+var questionSchemaTargets = []schemaTarget{
+	{schema: utils.QuestionSchemaFile, file: utils.QuestionsFile, ndjson: true},
+	{schema: utils.QuestionsManifestSchemaFile, file: utils.ManifestFile},
+}
+
+var geographySchemaTargets = []schemaTarget{
+	{schema: utils.CountrySchemaFile, file: utils.CountriesFile, ndjson: true},
+	{schema: utils.ContinentSchemaFile, file: utils.ContinentsFile, ndjson: true},
+	{schema: utils.RegionSchemaFile, file: utils.RegionsFile, ndjson: true},
+	{schema: utils.GeographyManifestSchemaFile, file: utils.GeographyManifestFile},
+}
+
+func ValidateQuestionSchemas() error {
+	r := NewReport("schemas")
+	checkSchemas(r, questionSchemaTargets)
+	return r.Err()
+}
+
+func ValidateGeographySchemas() error {
+	r := NewReport("schemas")
+	checkSchemas(r, geographySchemaTargets)
+	return r.Err()
+}
+
+func checkSchemas(r *Report, targets []schemaTarget) {
+	for _, target := range targets {
+		schema, err := LoadSchema(target.schema)
+		if err != nil {
+			r.addError("load-error", target.schema, 0, "", err)
+			continue
+		}
+
+		var schemaErrors []SchemaError
+		if target.ndjson {
+			schemaErrors, err = schema.ValidateNDJSONFile(target.file)
+		} else {
+			schemaErrors, err = schema.ValidateJSONFile(target.file)
+		}
+		if err != nil {
+			r.addError("load-error", target.file, 0, "", err)
+			continue
+		}
+		for _, e := range schemaErrors {
+			r.Add(Finding{Rule: "schema", File: e.File, Line: e.Line, Path: e.Pointer, Message: e.Message})
+		}
+	}
+}
+
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema %s: %w", path, err)
+	}
+	return ParseSchema(data)
+}
+
+func ParseSchema(data []byte) (*Schema, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &Schema{root: root, patterns: make(map[string]*regexp.Regexp)}, nil
+}
+
+func (s *Schema) ValidateNDJSONFile(path string) ([]SchemaError, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var errors []SchemaError
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		for _, e := range s.ValidateJSON([]byte(line)) {
+			e.File = path
+			e.Line = i + 1
+			errors = append(errors, e)
+		}
+	}
+	return errors, nil
+}
+
+func (s *Schema) ValidateJSONFile(path string) ([]SchemaError, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	errors := s.ValidateJSON(data)
+	if len(errors) == 0 {
+		return errors, nil
+	}
+	// Lines are only looked up in valid JSON, the scanner does not check the
+	// syntax.
+	var lines map[string]int
+	if json.Valid(data) {
+		lines = pointerLines(data)
+	}
+	for i := range errors {
+		errors[i].File = path
+		errors[i].Line = lineOf(lines, errors[i].Pointer)
+	}
+	return errors, nil
+}
+
+func (s *Schema) ValidateJSON(data []byte) []SchemaError {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return []SchemaError{{Message: fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+	return s.Validate(instance)
+}
+
+func (s *Schema) Validate(instance interface{}) []SchemaError {
+	return s.validate(s.root, instance, "")
+}
+
+func (s *Schema) validate(schema interface{}, instance interface{}, pointer string) []SchemaError {
+	switch schema := schema.(type) {
+	case bool:
+		if !schema {
+			return []SchemaError{{Pointer: pointer, Message: "no value is allowed here"}}
+		}
+		return nil
+	case map[string]interface{}:
+		return s.validateObjectSchema(schema, instance, pointer)
+	}
+	return nil
+}
+
+func (s *Schema) validateObjectSchema(schema map[string]interface{}, instance interface{}, pointer string) []SchemaError {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := s.resolve(ref)
+		if err != nil {
+			return []SchemaError{{Pointer: pointer, Message: err.Error()}}
+		}
+		return s.validate(resolved, instance, pointer)
+	}
+
+	var errors []SchemaError
+	fail := func(format string, args ...interface{}) {
+		errors = append(errors, SchemaError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(types, instance) {
+		fail("expected %s, got %s", describeTypes(types), jsonType(instance))
+		return errors
+	}
+
+	if expected, ok := schema["const"]; ok && !jsonEqual(expected, instance) {
+		fail("must be %s", formatJSON(expected))
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, v := range enum {
+			if jsonEqual(v, instance) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values := make([]string, len(enum))
+			for i, v := range enum {
+				values[i] = formatJSON(v)
+			}
+			fail("must be one of %s (got %s)", strings.Join(values, ", "), formatJSON(instance))
+		}
+	}
+
+	switch value := instance.(type) {
+	case string:
+		errors = append(errors, s.validateString(schema, value, pointer)...)
+	case json.Number:
+		errors = append(errors, validateNumber(schema, value, pointer)...)
+	case []interface{}:
+		errors = append(errors, s.validateArray(schema, value, pointer)...)
+	case map[string]interface{}:
+		errors = append(errors, s.validateObject(schema, value, pointer)...)
+	}
+
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			errors = append(errors, s.validate(sub, instance, pointer)...)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if len(s.validate(sub, instance, pointer)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("must match at least one schema in anyOf")
+		}
+	}
+	if one, ok := schema["oneOf"].([]interface{}); ok {
+		matched := 0
+		for _, sub := range one {
+			if len(s.validate(sub, instance, pointer)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail("must match exactly one schema in oneOf (matched %d)", matched)
+		}
+	}
+	if not, ok := schema["not"]; ok && len(s.validate(not, instance, pointer)) == 0 {
+		fail("must not match the schema in not")
+	}
+	if cond, ok := schema["if"]; ok {
+		if len(s.validate(cond, instance, pointer)) == 0 {
+			if then, ok := schema["then"]; ok {
+				errors = append(errors, s.validate(then, instance, pointer)...)
+			}
+		} else if otherwise, ok := schema["else"]; ok {
+			errors = append(errors, s.validate(otherwise, instance, pointer)...)
+		}
+	}
+
+	return errors
+}
+
+func (s *Schema) validateString(schema map[string]interface{}, value, pointer string) []SchemaError {
+	var errors []SchemaError
+	fail := func(format string, args ...interface{}) {
+		errors = append(errors, SchemaError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	length := len([]rune(value))
+	if min, ok := schemaInt(schema, "minLength"); ok && length < min {
+		fail("must be at least %d characters (got %d)", min, length)
+	}
+	if max, ok := schemaInt(schema, "maxLength"); ok && length > max {
+		fail("must be at most %d characters (got %d)", max, length)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := s.compile(pattern)
+		if err != nil {
+			fail("invalid pattern %q in schema: %v", pattern, err)
+		} else if !re.MatchString(value) {
+			fail("must match pattern %s (got %q)", pattern, value)
+		}
+	}
+	if format, ok := schema["format"].(string); ok {
+		if err := checkFormat(format, value); err != nil {
+			fail("must be a valid %s (got %q)", format, value)
+		}
+	}
+	return errors
+}
+
+func validateNumber(schema map[string]interface{}, value json.Number, pointer string) []SchemaError {
+	var errors []SchemaError
+	n, err := value.Float64()
+	if err != nil {
+		return []SchemaError{{Pointer: pointer, Message: fmt.Sprintf("invalid number %s", value)}}
+	}
+	fail := func(format string, args ...interface{}) {
+		errors = append(errors, SchemaError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if min, ok := schemaFloat(schema, "minimum"); ok && n < min {
+		fail("must be >= %v (got %s)", min, value)
+	}
+	if max, ok := schemaFloat(schema, "maximum"); ok && n > max {
+		fail("must be <= %v (got %s)", max, value)
+	}
+	if min, ok := schemaFloat(schema, "exclusiveMinimum"); ok && n <= min {
+		fail("must be > %v (got %s)", min, value)
+	}
+	if max, ok := schemaFloat(schema, "exclusiveMaximum"); ok && n >= max {
+		fail("must be < %v (got %s)", max, value)
+	}
+	if step, ok := schemaFloat(schema, "multipleOf"); ok && step > 0 {
+		if q := n / step; math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("must be a multiple of %v (got %s)", step, value)
+		}
+	}
+	return errors
+}
+
+func (s *Schema) validateArray(schema map[string]interface{}, items []interface{}, pointer string) []SchemaError {
+	var errors []SchemaError
+	fail := func(format string, args ...interface{}) {
+		errors = append(errors, SchemaError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if min, ok := schemaInt(schema, "minItems"); ok && len(items) < min {
+		fail("must have at least %d items (got %d)", min, len(items))
+	}
+	if max, ok := schemaInt(schema, "maxItems"); ok && len(items) > max {
+		fail("must have at most %d items (got %d)", max, len(items))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range items {
+			for j := 0; j < i; j++ {
+				if jsonEqual(items[i], items[j]) {
+					fail("items %d and %d must be unique", j, i)
+				}
+			}
+		}
+	}
+
+	switch itemSchema := schema["items"].(type) {
+	case []interface{}:
+		for i, item := range items {
+			if i < len(itemSchema) {
+				errors = append(errors, s.validate(itemSchema[i], item, pointer+"/"+strconv.Itoa(i))...)
+			} else if additional, ok := schema["additionalItems"]; ok {
+				errors = append(errors, s.validate(additional, item, pointer+"/"+strconv.Itoa(i))...)
+			}
+		}
+	case nil:
+	default:
+		for i, item := range items {
+			errors = append(errors, s.validate(itemSchema, item, pointer+"/"+strconv.Itoa(i))...)
+		}
+	}
+
+	if contains, ok := schema["contains"]; ok {
+		found := false
+		for _, item := range items {
+			if len(s.validate(contains, item, pointer)) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must contain at least one matching item")
+		}
+	}
+	return errors
+}
+
+func (s *Schema) validateObject(schema map[string]interface{}, object map[string]interface{}, pointer string) []SchemaError {
+	var errors []SchemaError
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, present := object[name]; !present {
+					errors = append(errors, SchemaError{Pointer: pointer, Message: fmt.Sprintf("missing required property %q", name)})
+				}
+			}
+		}
+	}
+	if min, ok := schemaInt(schema, "minProperties"); ok && len(object) < min {
+		errors = append(errors, SchemaError{Pointer: pointer, Message: fmt.Sprintf("must have at least %d properties (got %d)", min, len(object))})
+	}
+	if max, ok := schemaInt(schema, "maxProperties"); ok && len(object) > max {
+		errors = append(errors, SchemaError{Pointer: pointer, Message: fmt.Sprintf("must have at most %d properties (got %d)", max, len(object))})
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	patternProperties, _ := schema["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := schema["additionalProperties"]
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := object[key]
+		child := pointer + "/" + escapePointer(key)
+		matched := false
+
+		if sub, ok := properties[key]; ok {
+			matched = true
+			errors = append(errors, s.validate(sub, value, child)...)
+		}
+		for pattern, sub := range patternProperties {
+			re, err := s.compile(pattern)
+			if err != nil {
+				errors = append(errors, SchemaError{Pointer: child, Message: fmt.Sprintf("invalid pattern %q in schema: %v", pattern, err)})
+				continue
+			}
+			if re.MatchString(key) {
+				matched = true
+				errors = append(errors, s.validate(sub, value, child)...)
+			}
+		}
+		if !matched && hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				errors = append(errors, SchemaError{Pointer: child, Message: "additional property is not allowed"})
+			} else {
+				errors = append(errors, s.validate(additional, value, child)...)
+			}
+		}
+	}
+
+	if dependencies, ok := schema["dependencies"].(map[string]interface{}); ok {
+		for key, dependency := range dependencies {
+			if _, present := object[key]; !present {
+				continue
+			}
+			if names, ok := dependency.([]interface{}); ok {
+				for _, name := range names {
+					if _, present := object[name.(string)]; !present {
+						errors = append(errors, SchemaError{Pointer: pointer, Message: fmt.Sprintf("property %q requires %q", key, name)})
+					}
+				}
+			} else {
+				errors = append(errors, s.validate(dependency, object, pointer)...)
+			}
+		}
+	}
+
+	return errors
+}
+
+func (s *Schema) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref %q (only local references are supported)", ref)
+	}
+	var current interface{} = s.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		if current, ok = object[token]; !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+	return current, nil
+}
+
+func (s *Schema) compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := s.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	s.patterns[pattern] = re
+	return re, nil
+}
+
+func matchesType(types interface{}, instance interface{}) bool {
+	switch types := types.(type) {
+	case string:
+		return matchesSingleType(types, instance)
+	case []interface{}:
+		for _, t := range types {
+			if name, ok := t.(string); ok && matchesSingleType(name, instance) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesSingleType(name string, instance interface{}) bool {
+	switch name {
+	case "integer":
+		n, ok := instance.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := instance.(json.Number)
+		return ok
+	default:
+		return jsonType(instance) == name
+	}
+}
+
+func jsonType(instance interface{}) string {
+	switch instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+func describeTypes(types interface{}) string {
+	if list, ok := types.([]interface{}); ok {
+		names := make([]string, len(list))
+		for i, t := range list {
+			names[i] = fmt.Sprint(t)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(types)
+}
+
+func jsonEqual(a, b interface{}) bool {
+	if x, ok := a.(json.Number); ok {
+		a, _ = x.Float64()
+	}
+	if y, ok := b.(json.Number); ok {
+		b, _ = y.Float64()
+	}
+	switch x := a.(type) {
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			if other, ok := y[key]; !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+func formatJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func checkFormat(format, value string) error {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err
+	case "uri":
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return err
+		}
+		if parsed.Scheme == "" {
+			return fmt.Errorf("missing scheme")
+		}
+	case "email":
+		if !strings.Contains(value, "@") {
+			return fmt.Errorf("missing @")
+		}
+	}
+	return nil
+}
+
+func schemaInt(schema map[string]interface{}, key string) (int, bool) {
+	n, ok := schemaFloat(schema, key)
+	return int(n), ok
+}
+
+func schemaFloat(schema map[string]interface{}, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func pointerLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	p := &pointerScanner{data: data, line: 1, lines: lines}
+	p.value("")
+	return lines
+}
+
+func lineOf(lines map[string]int, pointer string) int {
+	for {
+		if line, ok := lines[pointer]; ok {
+			return line
+		}
+		i := strings.LastIndex(pointer, "/")
+		if i < 0 {
+			return 1
+		}
+		pointer = pointer[:i]
+	}
+}
+
+type pointerScanner struct {
+	data  []byte
+	pos   int
+	line  int
+	lines map[string]int
+}
+
+func (p *pointerScanner) skipSpace() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case '\n':
+			p.line++
+		case ' ', '\t', '\r':
+		default:
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *pointerScanner) value(pointer string) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return
+	}
+	p.lines[pointer] = p.line
+
+	switch p.data[p.pos] {
+	case '{':
+		p.pos++
+		for {
+			p.skipSpace()
+			if p.pos >= len(p.data) || p.data[p.pos] == '}' {
+				p.pos++
+				return
+			}
+			if p.data[p.pos] == ',' {
+				p.pos++
+				continue
+			}
+			key := p.str()
+			p.skipSpace()
+			if p.pos < len(p.data) && p.data[p.pos] == ':' {
+				p.pos++
+			}
+			p.value(pointer + "/" + escapePointer(key))
+		}
+	case '[':
+		p.pos++
+		for i := 0; ; {
+			p.skipSpace()
+			if p.pos >= len(p.data) || p.data[p.pos] == ']' {
+				p.pos++
+				return
+			}
+			if p.data[p.pos] == ',' {
+				p.pos++
+				continue
+			}
+			p.value(pointer + "/" + strconv.Itoa(i))
+			i++
+		}
+	case '"':
+		p.str()
+	default:
+		start := p.pos
+		for p.pos < len(p.data) && !strings.ContainsRune(",]} \t\r\n", rune(p.data[p.pos])) {
+			p.pos++
+		}
+		if p.pos == start {
+			// A stray delimiter: skip it so the enclosing loop moves on.
+			p.pos++
+		}
+	}
+}
+
+func (p *pointerScanner) str() string {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			var s string
+			_ = json.Unmarshal(p.data[start:p.pos], &s)
+			return s
+		}
+		p.pos++
+	}
+	return ""
+}
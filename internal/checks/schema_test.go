@@ -0,0 +1,192 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["slug", "answers"],
+  "additionalProperties": false,
+  "definitions": {
+    "label": {"type": "object", "required": ["label"], "properties": {"label": {"type": "string", "minLength": 1}}}
+  },
+  "properties": {
+    "slug": {"type": "string", "pattern": "^[a-z-]+$"},
+    "difficulty": {"enum": ["beginner", "pro"]},
+    "points": {"type": "number", "minimum": 0.5, "maximum": 5},
+    "count": {"type": "integer"},
+    "updated_at": {"type": "string", "format": "date-time"},
+    "answers": {
+      "type": "array",
+      "minItems": 2,
+      "items": {
+        "type": "object",
+        "properties": {
+          "i18n": {"type": "object", "additionalProperties": {"$ref": "#/definitions/label"}}
+        }
+      }
+    }
+  }
+}`
+
+func validateTestInstance(t *testing.T, instance string) []SchemaError {
+	t.Helper()
+	schema, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("ParseSchema() returned unexpected error: %v", err)
+	}
+	return schema.ValidateJSON([]byte(instance))
+}
+
+func TestSchemaValidate(t *testing.T) {
+	valid := `{"slug": "ok", "answers": [{"i18n": {"fr": {"label": "a"}}}, {}], "points": 1, "count": 3, "difficulty": "pro", "updated_at": "2025-01-01T00:00:00Z"}`
+	if errors := validateTestInstance(t, valid); len(errors) != 0 {
+		t.Fatalf("expected no errors, got %v", errors)
+	}
+
+	tests := []struct {
+		name     string
+		instance string
+		pointer  string
+		message  string
+	}{
+		{"missing required", `{"slug": "ok"}`, "", `missing required property "answers"`},
+		{"wrong type", `{"slug": 3, "answers": [{}, {}]}`, "/slug", "expected string, got number"},
+		{"pattern", `{"slug": "Not OK", "answers": [{}, {}]}`, "/slug", "must match pattern"},
+		{"enum", `{"slug": "ok", "difficulty": "hard", "answers": [{}, {}]}`, "/difficulty", "must be one of"},
+		{"maximum", `{"slug": "ok", "points": 10, "answers": [{}, {}]}`, "/points", "must be <= 5"},
+		{"integer", `{"slug": "ok", "count": 1.5, "answers": [{}, {}]}`, "/count", "expected integer"},
+		{"format", `{"slug": "ok", "updated_at": "yesterday", "answers": [{}, {}]}`, "/updated_at", "must be a valid date-time"},
+		{"min items", `{"slug": "ok", "answers": [{}]}`, "/answers", "at least 2 items"},
+		{"additional property", `{"slug": "ok", "answers": [{}, {}], "extra": true}`, "/extra", "additional property is not allowed"},
+		{"nested ref", `{"slug": "ok", "answers": [{}, {"i18n": {"en": {"label": ""}}}]}`, "/answers/1/i18n/en/label", "at least 1 characters"},
+		{"invalid json", `{"slug": `, "", "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validateTestInstance(t, tt.instance)
+			if len(errors) != 1 {
+				t.Fatalf("expected 1 error, got %v", errors)
+			}
+			if errors[0].Pointer != tt.pointer || !strings.Contains(errors[0].Message, tt.message) {
+				t.Errorf("got %q at %q, expected %q at %q", errors[0].Message, errors[0].Pointer, tt.message, tt.pointer)
+			}
+		})
+	}
+}
+
+func TestSchemaCombinators(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{
+		"type": "object",
+		"if": {"properties": {"qtype": {"const": "true_false"}}},
+		"then": {"properties": {"answers": {"maxItems": 2}}},
+		"else": {"properties": {"answers": {"minItems": 3}}},
+		"properties": {"value": {"oneOf": [{"type": "string"}, {"type": "integer"}]}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		instance string
+		errors   int
+	}{
+		{`{"qtype": "true_false", "answers": [1, 2]}`, 0},
+		{`{"qtype": "true_false", "answers": [1, 2, 3]}`, 1},
+		{`{"qtype": "single_choice", "answers": [1, 2]}`, 1},
+		{`{"value": "a"}`, 0},
+		{`{"value": true}`, 1},
+	}
+	for _, tt := range tests {
+		if errors := schema.ValidateJSON([]byte(tt.instance)); len(errors) != tt.errors {
+			t.Errorf("%s: expected %d errors, got %v", tt.instance, tt.errors, errors)
+		}
+	}
+}
+
+func TestSchemaErrorLines(t *testing.T) {
+	dir := t.TempDir()
+	schema, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ndjson := filepath.Join(dir, "items.ndjson")
+	content := `{"slug": "ok", "answers": [{}, {}]}` + "\n" + `{"slug": "ok", "answers": [{}]}` + "\n"
+	if err := os.WriteFile(ndjson, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	errors, err := schema.ValidateNDJSONFile(ndjson)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errors) != 1 || errors[0].Line != 2 || errors[0].Pointer != "/answers" {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+	if !strings.HasPrefix(errors[0].Error(), ndjson+":2: /answers: ") {
+		t.Errorf("unexpected error format: %s", errors[0].Error())
+	}
+
+	manifest := filepath.Join(dir, "manifest.json")
+	content = "{\n  \"slug\": \"ok\",\n  \"answers\": [\n    {},\n    {\n      \"i18n\": {\"fr\": {\"label\": 1}}\n    }\n  ]\n}\n"
+	if err := os.WriteFile(manifest, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	errors, err = schema.ValidateJSONFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errors) != 1 || errors[0].Pointer != "/answers/1/i18n/fr/label" || errors[0].Line != 6 {
+		t.Errorf("unexpected errors: %v", errors)
+	}
+
+	if err := os.WriteFile(manifest, []byte(`{"version":"1.0.0","counts":[1 }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	errors, err = schema.ValidateJSONFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errors) != 1 || !strings.HasPrefix(errors[0].Message, "invalid JSON") || errors[0].Line != 1 {
+		t.Errorf("unexpected errors: %v", errors)
+	}
+
+	for _, malformed := range []string{`{"counts":[1 }`, `[}`, `{"a":]`, `{"a" 1,}`, `[1,,]`} {
+		pointerLines([]byte(malformed))
+	}
+}
+
+func TestRepositorySchemas(t *testing.T) {
+	t.Chdir("../..")
+
+	if err := ValidateQuestionSchemas(); err != nil {
+		t.Errorf("questions dataset does not match its schemas:\n%v", err)
+	}
+	if err := ValidateGeographySchemas(); err != nil {
+		t.Errorf("geography dataset does not match its schemas:\n%v", err)
+	}
+
+	examples, err := filepath.Glob("schemas/*.example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, example := range examples {
+		schema, err := LoadSchema(strings.TrimSuffix(example, ".example.json") + ".schema.json")
+		if err != nil {
+			t.Fatalf("%s has no schema: %v", example, err)
+		}
+		errors, err := schema.ValidateJSONFile(example)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range errors {
+			t.Errorf("%v", e)
+		}
+	}
+}
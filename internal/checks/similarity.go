@@ -0,0 +1,150 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+const (
+	DefaultSimilarityThreshold = 0.6
+	stemSimilarityWeight       = 0.7
+	stemShingleSize            = 3
+)
+
+var stopwords = map[string]map[string]bool{
+	"en": utils.WordSet("a an and are as at be by did do does for from how in is it its of on or the this that to was were what when where which who whom with"),
+	"fr": utils.WordSet("a au aux avec c ce ces cette d dans de des du elle en est et il l la le les leur n ne ou par pas pour qu quand que quel quelle quelles quels qui s sa se son sont sur un une y"),
+	"es": utils.WordSet("a al como con cual cuales cuando de del donde el en es esta este fue la las lo los o para por que quien se su sus un una unos unas y"),
+}
+
+type questionFingerprint struct {
+	stem    map[string]bool
+	answers map[string]bool
+}
+
+func CheckSimilarQuestions(threshold float64) *Report {
+	r := NewReport("check-similar-questions")
+	questions, err := utils.LoadQuestions()
+	if err != nil {
+		r.addError("load-error", utils.QuestionsFile, 0, "", err)
+		return r
+	}
+	checkSimilarQuestions(r, utils.QuestionsFile, questions, threshold)
+	return r
+}
+
+func checkSimilarQuestions(r *Report, file string, questions []models.Question, threshold float64) {
+	fingerprints := make([]map[string]questionFingerprint, len(questions))
+	for i, q := range questions {
+		fingerprints[i] = fingerprintQuestion(q)
+	}
+
+	for j := range questions {
+		for i := 0; i < j; i++ {
+			if questions[i].Slug == questions[j].Slug {
+				continue
+			}
+			trueFalse := questions[i].Qtype == "true_false" && questions[j].Qtype == "true_false"
+			score, lang := bestSimilarity(fingerprints[i], fingerprints[j], trueFalse)
+			if score < threshold {
+				continue
+			}
+			r.Add(Finding{
+				Rule:    "similar-question",
+				File:    file,
+				Line:    j + 1,
+				Slug:    questions[j].Slug,
+				Related: questions[i].Slug,
+				Score:   score,
+				Message: fmt.Sprintf("similar to '%s' (line %d): score %.2f in %s", questions[i].Slug, i+1, score, lang),
+			})
+		}
+	}
+}
+
+func fingerprintQuestion(q models.Question) map[string]questionFingerprint {
+	fingerprints := make(map[string]questionFingerprint)
+	for lang, content := range q.I18n {
+		fp := questionFingerprint{
+			stem:    shingles(normalizeText(content.Stem, lang), stemShingleSize),
+			answers: make(map[string]bool),
+		}
+		for _, a := range q.Answers {
+			if label := normalizeText(a.I18n[lang].Label, lang); label != "" {
+				fp.answers[label] = true
+			}
+		}
+		fingerprints[lang] = fp
+	}
+	return fingerprints
+}
+
+func bestSimilarity(a, b map[string]questionFingerprint, trueFalse bool) (float64, string) {
+	langs := make([]string, 0, len(a))
+	for lang := range a {
+		if _, ok := b[lang]; ok {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+
+	best, bestLang := 0.0, ""
+	for _, lang := range langs {
+		score := jaccard(a[lang].stem, b[lang].stem)
+		if !trueFalse {
+			score = stemSimilarityWeight*score + (1-stemSimilarityWeight)*jaccard(a[lang].answers, b[lang].answers)
+		}
+		if score > best {
+			best, bestLang = score, lang
+		}
+	}
+	return math.Round(best*100) / 100, bestLang
+}
+
+func normalizeText(s, lang string) string {
+	var words []string
+	for _, token := range utils.Tokenize(s) {
+		if !stopwords[lang][token] {
+			words = append(words, token)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func shingles(s string, n int) map[string]bool {
+	set := make(map[string]bool)
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return set
+	}
+	if len(runes) <= n {
+		set[s] = true
+		return set
+	}
+	for i := 0; i+n <= len(runes); i++ {
+		set[string(runes[i:i+n])] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	small, large := a, b
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	intersection := 0
+	for k := range small {
+		if large[k] {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
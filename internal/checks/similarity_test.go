@@ -0,0 +1,102 @@
+package checks
+
+import (
+	"testing"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+func createSimilarityQuestion(slug, stemEN, stemFR string, labels ...string) models.Question {
+	q := createValidQuestion()
+	q.Slug = slug
+	q.I18n = map[string]models.I18n{
+		"en": {Stem: stemEN},
+		"fr": {Stem: stemFR},
+	}
+	for i := range q.Answers {
+		q.Answers[i].I18n = map[string]models.Label{"en": {Label: labels[i]}, "fr": {Label: labels[i]}}
+	}
+	return q
+}
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		input    string
+		lang     string
+		expected string
+	}{
+		{"What is the capital of France?", "en", "capital france"},
+		{"Quelle est la capitale de la FRANCE ?", "fr", "capitale france"},
+		{"¿Cuál es la capital de Francia?", "es", "capital francia"},
+		{"The Who", "fr", "the who"},
+	}
+	for _, tt := range tests {
+		if got := normalizeText(tt.input, tt.lang); got != tt.expected {
+			t.Errorf("normalizeText(%q, %s) = %q, expected %q", tt.input, tt.lang, got, tt.expected)
+		}
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	a := utils.WordSet("a b c d")
+	if got := jaccard(a, utils.WordSet("c d e f")); got != 2.0/6.0 {
+		t.Errorf("jaccard = %v, expected %v", got, 2.0/6.0)
+	}
+	if got := jaccard(a, a); got != 1 {
+		t.Errorf("jaccard of identical sets = %v", got)
+	}
+	if got := jaccard(a, utils.WordSet("")); got != 0 {
+		t.Errorf("jaccard with empty set = %v", got)
+	}
+}
+
+func TestCheckSimilarQuestions(t *testing.T) {
+	questions := []models.Question{
+		createSimilarityQuestion("capital-france", "What is the capital of France?", "Quelle est la capitale de la France ?", "Paris", "Lyon", "Marseille", "Nice"),
+		createSimilarityQuestion("moon-landing", "In which year did humans first land on the Moon?", "En quelle année l'homme a-t-il marché sur la Lune ?", "1969", "1972", "1965", "1959"),
+		createSimilarityQuestion("france-capital-city", "Which city is the capital of France?", "Quelle ville est la capitale de la France ?", "Nice", "Paris", "Lyon", "Bordeaux"),
+		createSimilarityQuestion("capital-spain", "What is the capital of Spain?", "Quelle est la capitale de l'Espagne ?", "Madrid", "Barcelona", "Seville", "Valencia"),
+	}
+
+	r := NewReport("check-similar-questions")
+	checkSimilarQuestions(r, "questions.ndjson", questions, DefaultSimilarityThreshold)
+	if len(r.Findings) != 1 {
+		t.Fatalf("expected 1 similar pair, got %+v", r.Findings)
+	}
+	f := r.Findings[0]
+	if f.Slug != "france-capital-city" || f.Related != "capital-france" || f.Line != 3 || f.Rule != "similar-question" || f.Severity != SeverityError {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+	if f.Score < DefaultSimilarityThreshold || f.Score > 1 {
+		t.Errorf("unexpected score %v", f.Score)
+	}
+
+	r = NewReport("check-similar-questions")
+	checkSimilarQuestions(r, "questions.ndjson", questions, 0.2)
+	found := false
+	for _, f := range r.Findings {
+		if f.Slug == "capital-spain" && f.Related == "capital-france" {
+			found = true
+		}
+		if f.Slug == "moon-landing" || f.Related == "moon-landing" {
+			t.Errorf("unrelated question reported: %+v", f)
+		}
+	}
+	if !found {
+		t.Errorf("lower threshold should report capital-spain, got %+v", r.Findings)
+	}
+
+	trueFalse := []models.Question{
+		createSimilarityQuestion("tf-one", "The Eiffel Tower is in Paris.", "La tour Eiffel est à Paris.", "True", "False", "", ""),
+		createSimilarityQuestion("tf-two", "The Great Wall is in China.", "La Grande Muraille est en Chine.", "True", "False", "", ""),
+	}
+	for i := range trueFalse {
+		trueFalse[i].Qtype = "true_false"
+	}
+	r = NewReport("check-similar-questions")
+	checkSimilarQuestions(r, "questions.ndjson", trueFalse, 0.3)
+	if len(r.Findings) != 0 {
+		t.Errorf("true/false answers should not count towards similarity, got %+v", r.Findings)
+	}
+}
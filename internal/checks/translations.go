@@ -0,0 +1,247 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+var translationLanguages = []string{"en", "fr", "es"}
+
+// Text of the new-question templates, compared after FoldText.
+var templateTexts = questionTemplateTexts()
+
+// Every slug of the new-question templates starts with this prefix.
+const templateSlugPrefix = "default-"
+
+// Lowercase words allowed inside a proper noun, as in "Leonardo da Vinci".
+var nameParticles = utils.WordSet("da de del della der des di du la le los of the van von y")
+
+type translatedText struct {
+	path string
+	name string
+	text map[string]string
+}
+
+func wordList(texts ...string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range texts {
+		set[strings.TrimSpace(utils.FoldText(t))] = true
+	}
+	return set
+}
+
+// questionTemplateTexts collects the titles, explanations, answer labels and
+// accepted variants of the new-question templates. Stems are left out because
+// most of them are ordinary questions ("What is the value?"), and so are the
+// true/false labels: every true/false question uses them.
+func questionTemplateTexts() map[string]bool {
+	var texts []string
+	for _, template := range utils.QuestionTemplates {
+		var q models.Question
+		if err := json.Unmarshal([]byte(template.Content), &q); err != nil {
+			continue
+		}
+		for _, content := range q.I18n {
+			texts = append(texts, content.Title, content.Explanation)
+		}
+		if q.Qtype == "true_false" {
+			continue
+		}
+		for _, a := range q.Answers {
+			for _, label := range a.I18n {
+				texts = append(texts, label.Label)
+			}
+			for _, accepted := range a.Accepted {
+				texts = append(texts, accepted...)
+			}
+		}
+	}
+	return wordList(texts...)
+}
+
+func checkTranslations(r *Report, file string, questions []models.Question) {
+	for i, q := range questions {
+		add := func(rule string, severity Severity, path, format string, args ...interface{}) {
+			r.Add(Finding{Rule: rule, Severity: severity, File: file, Line: i + 1, Slug: q.Slug, Path: path, Message: fmt.Sprintf(format, args...)})
+		}
+
+		for _, slug := range questionSlugs(q) {
+			if strings.HasPrefix(slug.slug, templateSlugPrefix) {
+				add("template-text", SeverityError, slug.path, "slug '%s' is still a template slug", slug.slug)
+			}
+		}
+
+		for _, lang := range translationLanguages {
+			if _, ok := q.I18n[lang]; !ok {
+				add("missing-translation", SeverityError, "/i18n/"+lang, "missing %s translation in title/question/explanation", lang)
+			}
+			for j, a := range q.Answers {
+				if _, ok := a.I18n[lang]; !ok {
+					add("missing-translation", SeverityError, fmt.Sprintf("/answers/%d/i18n/%s", j, lang), "missing %s translation in answer %d", lang, j+1)
+				}
+			}
+		}
+
+		for _, field := range questionTexts(q) {
+			for k, lang := range translationLanguages {
+				text, ok := field.text[lang]
+				if !ok {
+					continue
+				}
+				path := strings.ReplaceAll(field.path, "{lang}", lang)
+				trimmed := strings.TrimSpace(text)
+
+				if trimmed == "" {
+					add("empty-translation", SeverityError, path, "%s %s is empty", lang, field.name)
+					continue
+				}
+				if templateTexts[utils.FoldText(trimmed)] {
+					add("template-text", SeverityError, path, "%s %s is still the template text '%s'", lang, field.name, trimmed)
+					continue
+				}
+				if likelyUntranslatable(trimmed) {
+					continue
+				}
+				for _, other := range translationLanguages[:k] {
+					if strings.TrimSpace(field.text[other]) == trimmed {
+						add("untranslated-text", SeverityWarning, path, "%s %s is identical to the %s text '%s'", lang, field.name, other, trimmed)
+						break
+					}
+				}
+			}
+		}
+
+		if q.Qtype != "true_false" {
+			for _, lang := range translationLanguages {
+				content, ok := q.I18n[lang]
+				if !ok || strings.TrimSpace(content.Stem) == "" {
+					continue
+				}
+				if message := checkQuestionPunctuation(lang, strings.TrimSpace(content.Stem)); message != "" {
+					add("question-punctuation", SeverityWarning, "/i18n/"+lang+"/stem", "%s stem %s", lang, message)
+				}
+			}
+
+			// Text input answers are all correct and multiple choice ones may
+			// be, so mentioning one correct answer is enough.
+			for _, lang := range translationLanguages {
+				explanation := q.I18n[lang].Explanation
+				if strings.TrimSpace(explanation) == "" {
+					continue
+				}
+				var labels []string
+				mentioned := false
+				for _, a := range q.Answers {
+					label := strings.TrimSpace(a.I18n[lang].Label)
+					if !a.IsCorrect || label == "" {
+						continue
+					}
+					labels = append(labels, label)
+					if mentions(explanation, label, lang) {
+						mentioned = true
+						break
+					}
+				}
+				if !mentioned && len(labels) > 0 {
+					add("explanation-answer", SeverityWarning, "/i18n/"+lang+"/explanation", "%s explanation does not mention the correct answer '%s'", lang, strings.Join(labels, "' or '"))
+				}
+			}
+		}
+	}
+}
+
+type slugField struct {
+	path string
+	slug string
+}
+
+// questionSlugs lists the slugs a new question copies from its template.
+func questionSlugs(q models.Question) []slugField {
+	slugs := []slugField{{"/slug", q.Slug}, {"/theme/slug", q.Theme.Slug}}
+	for j, t := range q.Subthemes {
+		slugs = append(slugs, slugField{fmt.Sprintf("/subthemes/%d/slug", j), t.Slug})
+	}
+	for j, t := range q.Tags {
+		slugs = append(slugs, slugField{fmt.Sprintf("/tags/%d/slug", j), t.Slug})
+	}
+	for j, a := range q.Answers {
+		slugs = append(slugs, slugField{fmt.Sprintf("/answers/%d/slug", j), a.Slug})
+	}
+	return slugs
+}
+
+func questionTexts(q models.Question) []translatedText {
+	fields := []translatedText{
+		{path: "/i18n/{lang}/title", name: "title", text: map[string]string{}},
+		{path: "/i18n/{lang}/stem", name: "stem", text: map[string]string{}},
+		{path: "/i18n/{lang}/explanation", name: "explanation", text: map[string]string{}},
+	}
+	for lang, content := range q.I18n {
+		fields[0].text[lang] = content.Title
+		fields[1].text[lang] = content.Stem
+		fields[2].text[lang] = content.Explanation
+	}
+	for j, a := range q.Answers {
+		field := translatedText{path: fmt.Sprintf("/answers/%d/i18n/{lang}/label", j), name: fmt.Sprintf("answer %d label", j+1), text: map[string]string{}}
+		for lang, label := range a.I18n {
+			field.text[lang] = label.Label
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// likelyUntranslatable reports text that may legitimately be the same in every
+// language: numbers with their unit, symbols and proper nouns such as "Paris"
+// or "Leonardo da Vinci".
+func likelyUntranslatable(text string) bool {
+	afterNumber := false
+	for _, word := range strings.Fields(text) {
+		first := []rune(word)[0]
+		isUnit := afterNumber
+		afterNumber = unicode.IsDigit(first)
+		if unicode.IsUpper(first) || !unicode.IsLetter(first) || nameParticles[word] || isUnit {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func checkQuestionPunctuation(lang, stem string) string {
+	if !strings.HasSuffix(stem, "?") {
+		return "should end with '?'"
+	}
+	if lang == "es" && !strings.Contains(stem, "¿") {
+		return "should open the question with '¿'"
+	}
+	return ""
+}
+
+// mentions reports whether the explanation contains the label, or at least
+// every significant word of it.
+func mentions(explanation, label, lang string) bool {
+	if strings.Contains(utils.FoldText(explanation), utils.FoldText(label)) {
+		return true
+	}
+	words := make(map[string]bool)
+	for _, token := range utils.Tokenize(explanation) {
+		words[token] = true
+	}
+	found := false
+	for _, token := range utils.Tokenize(label) {
+		if stopwords[lang][token] {
+			continue
+		}
+		if !words[token] {
+			return false
+		}
+		found = true
+	}
+	return found
+}
@@ -0,0 +1,153 @@
+package checks
+
+import (
+	"testing"
+
+	"cultpedia/internal/models"
+	"cultpedia/internal/utils"
+)
+
+func createTranslatedQuestion() models.Question {
+	q := createValidQuestion()
+	q.I18n = map[string]models.I18n{
+		"en": {Title: "Renaissance painting", Stem: "Who painted the Mona Lisa?", Explanation: "The Mona Lisa was painted by Leonardo da Vinci."},
+		"fr": {Title: "Peinture de la Renaissance", Stem: "Qui a peint la Joconde ?", Explanation: "La Joconde a été peinte par Léonard de Vinci."},
+		"es": {Title: "Pintura del Renacimiento", Stem: "¿Quién pintó la Mona Lisa?", Explanation: "La Mona Lisa fue pintada por Leonardo da Vinci."},
+	}
+	labels := []map[string]string{
+		{"en": "Leonardo da Vinci", "fr": "Léonard de Vinci", "es": "Leonardo da Vinci"},
+		{"en": "Michelangelo", "fr": "Michel-Ange", "es": "Miguel Ángel"},
+		{"en": "1503", "fr": "1503", "es": "1503"},
+		{"en": "Raphael", "fr": "Raphaël", "es": "Rafael"},
+	}
+	for i := range q.Answers {
+		q.Answers[i].I18n = map[string]models.Label{}
+		for lang, label := range labels[i] {
+			q.Answers[i].I18n[lang] = models.Label{Label: label}
+		}
+	}
+	return q
+}
+
+func TestCheckTranslations(t *testing.T) {
+	r := NewReport("check-translations")
+	checkTranslations(r, "questions.ndjson", []models.Question{createTranslatedQuestion()})
+	if len(r.Findings) != 0 {
+		t.Fatalf("well translated question should have no findings, got %+v", r.Findings)
+	}
+
+	tests := []struct {
+		name     string
+		change   func(*models.Question)
+		rule     string
+		severity Severity
+		path     string
+	}{
+		{"missing language", func(q *models.Question) { delete(q.I18n, "es") }, "missing-translation", SeverityError, "/i18n/es"},
+		{"empty label", func(q *models.Question) { q.Answers[1].I18n["fr"] = models.Label{Label: " "} }, "empty-translation", SeverityError, "/answers/1/i18n/fr/label"},
+		{"template title", func(q *models.Question) {
+			q.I18n["en"] = models.I18n{Title: "Default Title", Stem: q.I18n["en"].Stem, Explanation: q.I18n["en"].Explanation}
+		}, "template-text", SeverityError, "/i18n/en/title"},
+		{"template answer slug", func(q *models.Question) { q.Answers[2].Slug = "default-answer3" }, "template-text", SeverityError, "/answers/2/slug"},
+		{"template label", func(q *models.Question) { q.Answers[3].I18n["fr"] = models.Label{Label: "Réponse 4"} }, "template-text", SeverityError, "/answers/3/i18n/fr/label"},
+		{"untranslated title", func(q *models.Question) {
+			es := q.I18n["es"]
+			es.Title = "Renaissance painting"
+			q.I18n["es"] = es
+		}, "untranslated-text", SeverityWarning, "/i18n/es/title"},
+		{"missing opening question mark", func(q *models.Question) {
+			es := q.I18n["es"]
+			es.Stem = "Quién pintó la Mona Lisa?"
+			q.I18n["es"] = es
+		}, "question-punctuation", SeverityWarning, "/i18n/es/stem"},
+		{"missing question mark", func(q *models.Question) {
+			fr := q.I18n["fr"]
+			fr.Stem = "Qui a peint la Joconde"
+			q.I18n["fr"] = fr
+		}, "question-punctuation", SeverityWarning, "/i18n/fr/stem"},
+		{"explanation without answer", func(q *models.Question) {
+			en := q.I18n["en"]
+			en.Explanation = "The Mona Lisa hangs in the Louvre."
+			q.I18n["en"] = en
+		}, "explanation-answer", SeverityWarning, "/i18n/en/explanation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := createTranslatedQuestion()
+			tt.change(&q)
+
+			r := NewReport("check-translations")
+			checkTranslations(r, "questions.ndjson", []models.Question{q})
+			if len(r.Findings) != 1 {
+				t.Fatalf("expected 1 finding, got %+v", r.Findings)
+			}
+			f := r.Findings[0]
+			if f.Rule != tt.rule || f.Severity != tt.severity || f.Path != tt.path {
+				t.Errorf("got %s (%s) at %s, expected %s (%s) at %s", f.Rule, f.Severity, f.Path, tt.rule, tt.severity, tt.path)
+			}
+		})
+	}
+}
+
+func TestCheckTranslationsTrueFalse(t *testing.T) {
+	q := createTranslatedQuestion()
+	q.Qtype = "true_false"
+	q.I18n["en"] = models.I18n{Title: "Renaissance painting", Stem: "Leonardo da Vinci painted the Mona Lisa.", Explanation: "He painted it in the early 16th century."}
+	q.Answers = []models.Answer{
+		{Slug: "true", IsCorrect: true, I18n: map[string]models.Label{"en": {Label: "True"}, "fr": {Label: "Vrai"}, "es": {Label: "Verdadero"}}},
+		{Slug: "false", I18n: map[string]models.Label{"en": {Label: "False"}, "fr": {Label: "Faux"}, "es": {Label: "Falso"}}},
+	}
+
+	r := NewReport("check-translations")
+	checkTranslations(r, "questions.ndjson", []models.Question{q})
+	if len(r.Findings) != 0 {
+		t.Errorf("true/false statements and explanations should not be checked, got %+v", r.Findings)
+	}
+}
+
+func TestCheckTranslationsTextInput(t *testing.T) {
+	q := createTranslatedQuestion()
+	q.Qtype = "text_input"
+	q.I18n["en"] = models.I18n{Title: "Renaissance painting", Stem: "What is the answer?", Explanation: "The Mona Lisa was painted by Leonardo da Vinci."}
+	for i := range q.Answers {
+		q.Answers[i].IsCorrect = true
+	}
+
+	r := NewReport("check-translations")
+	checkTranslations(r, "questions.ndjson", []models.Question{q})
+	if len(r.Findings) != 0 {
+		t.Errorf("mentioning one accepted answer should be enough, got %+v", r.Findings)
+	}
+}
+
+func TestQuestionTemplateTexts(t *testing.T) {
+	for _, text := range []string{"Default Title", "Explicación por defecto.", "Événement 4", "Variant 1", "Titre numérique"} {
+		if !templateTexts[utils.FoldText(text)] {
+			t.Errorf("%q should be a template text", text)
+		}
+	}
+	for _, text := range []string{"In what order did these events happen?", "¿En qué orden ocurrieron estos eventos?", "Which answers are correct?", "What is the value?", "What is the answer?"} {
+		if templateTexts[utils.FoldText(text)] {
+			t.Errorf("template stem %q should not be a template text", text)
+		}
+	}
+	for _, text := range []string{"True", "Faux", "Verdadero"} {
+		if templateTexts[utils.FoldText(text)] {
+			t.Errorf("true/false label %q should not be a template text", text)
+		}
+	}
+}
+
+func TestLikelyUntranslatable(t *testing.T) {
+	for _, text := range []string{"1789", "Paris", "Leonardo da Vinci", "J. S. Bach", "H2O", "100 km/h"} {
+		if !likelyUntranslatable(text) {
+			t.Errorf("likelyUntranslatable(%q) = false", text)
+		}
+	}
+	for _, text := range []string{"the moon", "Renaissance painting", "jazz"} {
+		if likelyUntranslatable(text) {
+			t.Errorf("likelyUntranslatable(%q) = true", text)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package checks
+
+import (
+	"crypto/ed25519"
+
+	"cultpedia/pkg/manifest"
+)
+
+var mismatchRules = map[string]string{
+	manifest.MismatchChecksum:  "checksum-mismatch",
+	manifest.MismatchCount:     "count-mismatch",
+	manifest.MismatchMissing:   "missing-file",
+	manifest.MismatchInvalid:   "invalid-manifest",
+	manifest.MismatchSignature: "invalid-signature",
+}
+
+func VerifyManifests(publicKey ed25519.PublicKey, locations ...string) *Report {
+	r := NewReport("verify")
+	verifier := &manifest.Verifier{PublicKey: publicKey}
+	for _, location := range locations {
+		result, err := verifier.Verify(location)
+		if err != nil {
+			r.addError("load-error", location, 0, "", err)
+			continue
+		}
+
+		for _, m := range result.Mismatches {
+			path := "/checksums/" + escapePointer(m.Name)
+			switch m.Kind {
+			case manifest.MismatchCount:
+				path = "/counts/" + escapePointer(m.Name)
+			case manifest.MismatchSignature:
+				path = ""
+			}
+			r.Add(Finding{Rule: mismatchRules[m.Kind], File: location, Path: path, Message: m.Detail()})
+		}
+	}
+	return r
+}
@@ -1,11 +1,19 @@
 package models
 
+import "time"
+
 type APIData struct {
 	Questions  []Question  `json:"questions"`
 	Countries  []Country   `json:"countries"`
 	Regions    []Region    `json:"regions"`
 	Continents []Continent `json:"continents"`
 	Manifests  Manifests   `json:"manifests"`
+
+	QuestionIndex  map[string]int `json:"-"`
+	CountryIndex   map[string]int `json:"-"`
+	RegionIndex    map[string]int `json:"-"`
+	ContinentIndex map[string]int `json:"-"`
+	Search         *SearchIndex   `json:"-"`
 }
 
 type Manifests struct {
@@ -16,6 +24,9 @@ type Manifests struct {
 type ManifestInfo struct {
 	Version   string `json:"version"`
 	UpdatedAt string `json:"updated_at"`
+
+	ETag         string    `json:"-"`
+	LastModified time.Time `json:"-"`
 }
 
 type APIRootResponse struct {
@@ -32,3 +43,65 @@ type EndpointInfo struct {
 	Method      string `json:"method"`
 	Description string `json:"description"`
 }
+
+type ListResponse struct {
+	Data  interface{} `json:"data"`
+	Count int         `json:"count"`
+}
+
+type PaginatedResponse struct {
+	Data       interface{} `json:"data"`
+	Count      int         `json:"count"`
+	Total      int         `json:"total"`
+	Limit      int         `json:"limit"`
+	Offset     int         `json:"offset"`
+	NextOffset *int        `json:"next_offset,omitempty"`
+}
+
+type ItemResponse struct {
+	Data interface{} `json:"data"`
+}
+
+type QuizResponse struct {
+	Seed  int64       `json:"seed"`
+	Count int         `json:"count"`
+	Data  interface{} `json:"data"`
+}
+
+type AnswerSubmission struct {
+	Answer  string   `json:"answer,omitempty"`
+	Answers []string `json:"answers,omitempty"`
+	Value   *float64 `json:"value,omitempty"`
+	Lang    string   `json:"lang,omitempty"`
+}
+
+type AnswerResult struct {
+	Question       string          `json:"question"`
+	Answer         string          `json:"answer,omitempty"`
+	Answers        []string        `json:"answers,omitempty"`
+	Correct        bool            `json:"correct"`
+	CorrectAnswer  *CorrectAnswer  `json:"correct_answer,omitempty"`
+	CorrectAnswers []CorrectAnswer `json:"correct_answers,omitempty"`
+	Value          *float64        `json:"value,omitempty"`
+	CorrectValue   *NumericAnswer  `json:"correct_value,omitempty"`
+	Explanation    string          `json:"explanation"`
+	Lang           string          `json:"lang"`
+	Points         float64         `json:"points"`
+	MaxPoints      float64         `json:"max_points"`
+}
+
+type CorrectAnswer struct {
+	Slug  string `json:"slug"`
+	Label string `json:"label"`
+}
+
+type ReloadResponse struct {
+	Status    string         `json:"status"`
+	Manifests Manifests      `json:"manifests"`
+	Stats     map[string]int `json:"stats"`
+}
+
+type ErrorResponse struct {
+	Error  string `json:"error"`
+	Status int    `json:"status"`
+}
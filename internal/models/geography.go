@@ -49,6 +49,47 @@ type Region struct {
 	Countries []string          `json:"countries"`
 }
 
+type LocalizedCountry struct {
+	Slug         string      `json:"slug"`
+	ISOAlpha2    string      `json:"iso_alpha2"`
+	ISOAlpha3    string      `json:"iso_alpha3"`
+	ISONumerics  string      `json:"iso_numeric"`
+	Lang         string      `json:"lang"`
+	Name         string      `json:"name"`
+	OfficialName string      `json:"official_name"`
+	Capital      string      `json:"capital"`
+	Continent    string      `json:"continent"`
+	Region       string      `json:"region"`
+	Coordinates  Coordinates `json:"coordinates"`
+	Flag         string      `json:"flag"`
+	Population   int64       `json:"population"`
+	AreaKm2      float64     `json:"area_km2"`
+	Currency     Currency    `json:"currency"`
+	Languages    []string    `json:"languages"`
+	Neighbors    []string    `json:"neighbors"`
+	TLD          string      `json:"tld"`
+	PhoneCode    string      `json:"phone_code"`
+	DrivingSide  string      `json:"driving_side"`
+	UNMember     bool        `json:"un_member"`
+}
+
+type LocalizedContinent struct {
+	Slug       string   `json:"slug"`
+	Lang       string   `json:"lang"`
+	Name       string   `json:"name"`
+	Countries  []string `json:"countries"`
+	AreaKm2    float64  `json:"area_km2"`
+	Population int64    `json:"population"`
+}
+
+type LocalizedRegion struct {
+	Slug      string   `json:"slug"`
+	Lang      string   `json:"lang"`
+	Name      string   `json:"name"`
+	Continent string   `json:"continent"`
+	Countries []string `json:"countries"`
+}
+
 type GeographyManifest struct {
 	SchemaVersion string              `json:"schema_version"`
 	Dataset       string              `json:"dataset"`
@@ -12,8 +12,13 @@ type Question struct {
 	EstimatedSeconds int             `json:"estimated_seconds"`
 	Points           float64         `json:"points"`
 	ShuffleAnswers   bool            `json:"shuffle_answers"`
+	Scoring          string          `json:"scoring,omitempty"`
+	TypoTolerance    int             `json:"typo_tolerance,omitempty"`
 	I18n             map[string]I18n `json:"i18n"`
 	Answers          []Answer        `json:"answers"`
+	Numeric          *NumericAnswer  `json:"numeric,omitempty"`
+	Media            *Media          `json:"media,omitempty"`
+	Flag             string          `json:"flag,omitempty"`
 	Sources          []string        `json:"sources,omitempty"`
 }
 
@@ -28,11 +33,101 @@ type I18n struct {
 }
 
 type Answer struct {
-	Slug      string           `json:"slug"`
-	IsCorrect bool             `json:"is_correct"`
-	I18n      map[string]Label `json:"i18n"`
+	Slug      string              `json:"slug"`
+	IsCorrect bool                `json:"is_correct"`
+	Position  int                 `json:"position,omitempty"`
+	I18n      map[string]Label    `json:"i18n"`
+	Accepted  map[string][]string `json:"accepted,omitempty"`
 }
 
 type Label struct {
 	Label string `json:"label"`
 }
+
+// NumericAnswer is the expected value of a numeric question. An answer is
+// correct within Tolerance of Value, or within RelativeTolerance (0.1 for
+// 10%) of it; with neither set the value must match exactly.
+type NumericAnswer struct {
+	Value             float64 `json:"value"`
+	Unit              string  `json:"unit,omitempty"`
+	Tolerance         float64 `json:"tolerance,omitempty"`
+	RelativeTolerance float64 `json:"relative_tolerance,omitempty"`
+}
+
+// Media is an image or audio file shown with a question. File is the name of
+// the file in datasets/general-knowledge/assets, served by /api/media/{file}.
+// Country flags are not media: Question.Flag holds the ISO alpha-2 code of
+// the flag served by /api/geography/flags/{code}. Player views replace it
+// with FlagURL, /api/questions/{slug}/flag, which does not name the country.
+type Media struct {
+	File        string `json:"file"`
+	License     string `json:"license"`
+	Attribution string `json:"attribution"`
+	SourceURL   string `json:"source_url,omitempty"`
+}
+
+type PlayerQuestion struct {
+	Kind             string                `json:"kind"`
+	Version          string                `json:"version,omitempty"`
+	Slug             string                `json:"slug"`
+	Theme            Theme                 `json:"theme"`
+	Subthemes        []Theme               `json:"subthemes,omitempty"`
+	Tags             []Theme               `json:"tags,omitempty"`
+	Qtype            string                `json:"qtype"`
+	Difficulty       string                `json:"difficulty"`
+	EstimatedSeconds int                   `json:"estimated_seconds"`
+	Points           float64               `json:"points"`
+	ShuffleAnswers   bool                  `json:"shuffle_answers"`
+	Scoring          string                `json:"scoring,omitempty"`
+	TypoTolerance    int                   `json:"typo_tolerance,omitempty"`
+	I18n             map[string]PlayerI18n `json:"i18n"`
+	Answers          []PlayerAnswer        `json:"answers"`
+	Unit             string                `json:"unit,omitempty"`
+	Media            *Media                `json:"media,omitempty"`
+	FlagURL          string                `json:"flag_url,omitempty"`
+}
+
+type PlayerI18n struct {
+	Title string `json:"title"`
+	Stem  string `json:"stem"`
+}
+
+type PlayerAnswer struct {
+	Slug string           `json:"slug"`
+	I18n map[string]Label `json:"i18n"`
+}
+
+type LocalizedQuestion struct {
+	Kind             string            `json:"kind"`
+	Version          string            `json:"version,omitempty"`
+	Slug             string            `json:"slug"`
+	Theme            Theme             `json:"theme"`
+	Subthemes        []Theme           `json:"subthemes,omitempty"`
+	Tags             []Theme           `json:"tags,omitempty"`
+	Qtype            string            `json:"qtype"`
+	Difficulty       string            `json:"difficulty"`
+	EstimatedSeconds int               `json:"estimated_seconds"`
+	Points           float64           `json:"points"`
+	ShuffleAnswers   bool              `json:"shuffle_answers"`
+	Scoring          string            `json:"scoring,omitempty"`
+	TypoTolerance    int               `json:"typo_tolerance,omitempty"`
+	Lang             string            `json:"lang"`
+	Title            string            `json:"title"`
+	Stem             string            `json:"stem"`
+	Explanation      string            `json:"explanation,omitempty"`
+	Answers          []LocalizedAnswer `json:"answers"`
+	Numeric          *NumericAnswer    `json:"numeric,omitempty"`
+	Unit             string            `json:"unit,omitempty"`
+	Media            *Media            `json:"media,omitempty"`
+	Flag             string            `json:"flag,omitempty"`
+	FlagURL          string            `json:"flag_url,omitempty"`
+	Sources          []string          `json:"sources,omitempty"`
+}
+
+type LocalizedAnswer struct {
+	Slug      string   `json:"slug"`
+	IsCorrect *bool    `json:"is_correct,omitempty"`
+	Position  int      `json:"position,omitempty"`
+	Label     string   `json:"label"`
+	Accepted  []string `json:"accepted,omitempty"`
+}
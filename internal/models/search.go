@@ -0,0 +1,24 @@
+package models
+
+type SearchIndex struct {
+	Fields   []SearchField
+	Postings map[string][]int
+	Tokens   []string
+}
+
+type SearchField struct {
+	Type  string
+	Slug  string
+	Field string
+	Lang  string
+	Text  string
+}
+
+type SearchResult struct {
+	Type  string  `json:"type"`
+	Slug  string  `json:"slug"`
+	Field string  `json:"field"`
+	Lang  string  `json:"lang"`
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+}
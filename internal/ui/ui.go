@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	"cultpedia/internal/actions"
@@ -19,8 +21,12 @@ const (
 	keyEnter = "enter"
 	keyEsc   = "esc"
 
-	qtypeSingleChoice = "single_choice"
-	qtypeTrueFalse    = "true_false"
+	qtypeSingleChoice   = "single_choice"
+	qtypeTrueFalse      = "true_false"
+	qtypeMultipleChoice = "multiple_choice"
+	qtypeOrdering       = "ordering"
+	qtypeNumeric        = "numeric"
+	qtypeTextInput      = "text_input"
 )
 
 var titleStyle = lipgloss.NewStyle().
@@ -272,17 +278,66 @@ func (m previewModel) View() string {
 
 	s += fmt.Sprintf("  Difficulty: %s | Points: %.1f | Type: %s\n", m.question.Difficulty, m.question.Points, m.question.Qtype)
 	s += fmt.Sprintf("  Languages: ✓ fr ✓ en ✓ es | Answers: %d | Sources: %d\n", len(m.question.Answers), len(m.question.Sources))
+	scoring := m.question.Scoring
+	if scoring == "" {
+		scoring = "all_or_nothing"
+	}
+	switch m.question.Qtype {
+	case qtypeMultipleChoice:
+		correct := 0
+		for _, a := range m.question.Answers {
+			if a.IsCorrect {
+				correct++
+			}
+		}
+		s += fmt.Sprintf("  Correct answers: %d | Scoring: %s\n", correct, scoring)
+	case qtypeOrdering, qtypeNumeric:
+		s += fmt.Sprintf("  Scoring: %s\n", scoring)
+	case qtypeTextInput:
+		s += fmt.Sprintf("  Typo tolerance: %d\n", m.question.TypoTolerance)
+	}
 
 	content := m.question.I18n[currentLang]
 	s += "\n" + boxStyle.Render(fmt.Sprintf("Title (%s): %s\n\nQuestion: %s\n\nExplanation: %s", strings.ToUpper(currentLang), content.Title, content.Stem, content.Explanation))
 
-	s += "\n\nAnswers:\n"
-	for _, choice := range m.question.Answers {
-		correctMark := " "
-		if choice.IsCorrect {
-			correctMark = "✓"
+	if m.question.Qtype == qtypeNumeric && m.question.Numeric != nil {
+		n := m.question.Numeric
+		tolerance := "exact"
+		if n.Tolerance > 0 {
+			tolerance = fmt.Sprintf("± %g", n.Tolerance)
+		} else if n.RelativeTolerance > 0 {
+			tolerance = fmt.Sprintf("± %g%%", n.RelativeTolerance*100)
+		}
+		value := strconv.FormatFloat(n.Value, 'f', -1, 64)
+		s += fmt.Sprintf("\n\nAnswer:\n  %s (%s)\n", strings.TrimSpace(value+" "+n.Unit), tolerance)
+	} else if m.question.Qtype == qtypeOrdering {
+		ordered := make([]models.Answer, len(m.question.Answers))
+		copy(ordered, m.question.Answers)
+		sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Position < ordered[j].Position })
+
+		s += "\n\nCorrect order:\n"
+		for _, choice := range ordered {
+			s += fmt.Sprintf("  %d. %s\n", choice.Position, choice.I18n[currentLang].Label)
+		}
+	} else if m.question.Qtype == qtypeTextInput {
+		s += "\n\nAccepted answers:\n"
+		for _, choice := range m.question.Answers {
+			variants := append([]string{choice.I18n[currentLang].Label}, choice.Accepted[currentLang]...)
+			s += fmt.Sprintf("  - %s\n", strings.Join(variants, " / "))
+		}
+	} else {
+		s += "\n\nAnswers:\n"
+		for _, choice := range m.question.Answers {
+			correctMark := " "
+			if choice.IsCorrect {
+				correctMark = "✓"
+			}
+			s += fmt.Sprintf("  [%s] %s\n", correctMark, choice.I18n[currentLang].Label)
 		}
-		s += fmt.Sprintf("  [%s] %s\n", correctMark, choice.I18n[currentLang].Label)
+	}
+
+	if media := m.question.Media; media != nil {
+		s += fmt.Sprintf("\nMedia:\n  %s (%s, %s)\n", media.File, media.License, media.Attribution)
 	}
 
 	s += "\n\n" + infoStyle.Render("Commands: [←→] Change Language | [Enter/Esc] Back | [?] Help | [q] Quit")
@@ -434,12 +489,25 @@ func (m mainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}, nil
 				}
 			case 3:
-				if m.questionType == qtypeSingleChoice {
+				switch m.questionType {
+				case qtypeSingleChoice:
 					m.questionType = qtypeTrueFalse
-					m.message = "Switched to True/False mode\nEdit: datasets/new-question-true-false.json"
-				} else {
+					m.message = "Switched to True/False mode\nEdit: " + utils.NewQuestionTrueFalseFile
+				case qtypeTrueFalse:
+					m.questionType = qtypeMultipleChoice
+					m.message = "Switched to Multiple Choice mode\nEdit: " + utils.NewQuestionMultipleChoiceFile
+				case qtypeMultipleChoice:
+					m.questionType = qtypeOrdering
+					m.message = "Switched to Ordering mode\nEdit: " + utils.NewQuestionOrderingFile
+				case qtypeOrdering:
+					m.questionType = qtypeNumeric
+					m.message = "Switched to Numeric mode\nEdit: " + utils.NewQuestionNumericFile
+				case qtypeNumeric:
+					m.questionType = qtypeTextInput
+					m.message = "Switched to Text Input mode\nEdit: " + utils.NewQuestionTextInputFile
+				default:
 					m.questionType = qtypeSingleChoice
-					m.message = "Switched to Single Choice mode\nEdit: datasets/new-question.json"
+					m.message = "Switched to Single Choice mode\nEdit: " + utils.NewQuestionFile
 				}
 			}
 		case "?":
@@ -457,10 +525,23 @@ func (m mainMenuModel) View() string {
 	s += versionStr + "\n"
 
 	modeDisplay := qtypeSingleChoice
-	templateFile := "datasets/new-question.json"
-	if m.questionType == qtypeTrueFalse {
+	templateFile := utils.NewQuestionFile
+	switch m.questionType {
+	case qtypeTrueFalse:
 		modeDisplay = qtypeTrueFalse
-		templateFile = "datasets/new-question-true-false.json"
+		templateFile = utils.NewQuestionTrueFalseFile
+	case qtypeMultipleChoice:
+		modeDisplay = qtypeMultipleChoice
+		templateFile = utils.NewQuestionMultipleChoiceFile
+	case qtypeOrdering:
+		modeDisplay = qtypeOrdering
+		templateFile = utils.NewQuestionOrderingFile
+	case qtypeNumeric:
+		modeDisplay = qtypeNumeric
+		templateFile = utils.NewQuestionNumericFile
+	case qtypeTextInput:
+		modeDisplay = qtypeTextInput
+		templateFile = utils.NewQuestionTextInputFile
 	}
 	s += infoStyle.Render(fmt.Sprintf("Mode: %s | Template: %s", modeDisplay, templateFile)) + "\n\n"
 
@@ -0,0 +1,193 @@
+package utils
+
+// QuestionTemplate is the new-question template of a question type and the
+// file it is reset into.
+type QuestionTemplate struct {
+	File    string
+	Content string
+}
+
+// QuestionTemplates holds the new-question template of every question type.
+// The translation checks reject questions that still use their texts.
+var QuestionTemplates = map[string]QuestionTemplate{
+	"single_choice":   {File: NewQuestionFile, Content: singleChoiceTemplate},
+	"true_false":      {File: NewQuestionTrueFalseFile, Content: trueFalseTemplate},
+	"multiple_choice": {File: NewQuestionMultipleChoiceFile, Content: multipleChoiceTemplate},
+	"ordering":        {File: NewQuestionOrderingFile, Content: orderingTemplate},
+	"numeric":         {File: NewQuestionNumericFile, Content: numericTemplate},
+	"text_input":      {File: NewQuestionTextInputFile, Content: textInputTemplate},
+}
+
+const singleChoiceTemplate = `{
+  "kind": "question",
+  "version": "1.0",
+  "slug": "default-question-slug",
+  "theme": { "slug": "default-theme" },
+  "subthemes": [ { "slug": "default-subtheme1" }, { "slug": "default-subtheme2" } ],
+  "tags": [ { "slug": "default-tag1" }, { "slug": "default-tag2" } ],
+  "qtype": "single_choice",
+  "difficulty": "beginner",
+  "estimated_seconds": 15,
+  "points": 1.0,
+  "shuffle_answers": true,
+  "i18n": {
+    "fr": { "title": "Titre par défaut", "stem": "Question par défaut ?", "explanation": "Explication par défaut." },
+    "en": { "title": "Default Title", "stem": "Default question?", "explanation": "Default explanation." },
+    "es": { "title": "Título por defecto", "stem": "¿Pregunta por defecto?", "explanation": "Explicación por defecto." }
+  },
+  "answers": [
+    { "slug": "default-answer1", "is_correct": true,  "i18n": { "fr": { "label": "Réponse 1" }, "en": { "label": "Answer 1" }, "es": { "label": "Respuesta 1" } } },
+    { "slug": "default-answer2", "is_correct": false, "i18n": { "fr": { "label": "Réponse 2" }, "en": { "label": "Answer 2" }, "es": { "label": "Respuesta 2" } } },
+    { "slug": "default-answer3", "is_correct": false, "i18n": { "fr": { "label": "Réponse 3" }, "en": { "label": "Answer 3" }, "es": { "label": "Respuesta 3" } } },
+    { "slug": "default-answer4", "is_correct": false, "i18n": { "fr": { "label": "Réponse 4" }, "en": { "label": "Answer 4" }, "es": { "label": "Respuesta 4" } } }
+  ],
+  "sources": [
+    "https://example.com/default-source"
+  ]
+}
+`
+
+const trueFalseTemplate = `{
+  "kind": "question",
+  "version": "1.0",
+  "slug": "default-true-false-question-slug",
+  "theme": { "slug": "default-theme" },
+  "subthemes": [ { "slug": "default-subtheme1" }, { "slug": "default-subtheme2" } ],
+  "tags": [ { "slug": "default-tag1" }, { "slug": "default-tag2" } ],
+  "qtype": "true_false",
+  "difficulty": "beginner",
+  "estimated_seconds": 10,
+  "points": 1.0,
+  "shuffle_answers": false,
+  "i18n": {
+    "fr": { "title": "Titre vrai/faux", "stem": "Cette affirmation est-elle vraie ou fausse?", "explanation": "Explication par défaut à remplacer par votre explication détaillée." },
+    "en": { "title": "True/False Title", "stem": "Is this statement true or false?", "explanation": "Default explanation to replace with your detailed explanation." },
+    "es": { "title": "Titulo verdadero/falso", "stem": "Es verdadera o falsa esta afirmacion?", "explanation": "Explicación por defecto a reemplazar con su explicación detallada." }
+  },
+  "answers": [
+    { "slug": "true", "is_correct": true, "i18n": { "fr": { "label": "Vrai" }, "en": { "label": "True" }, "es": { "label": "Verdadero" } } },
+    { "slug": "false", "is_correct": false, "i18n": { "fr": { "label": "Faux" }, "en": { "label": "False" }, "es": { "label": "Falso" } } }
+  ],
+  "sources": [
+    "https://example.com/default-source"
+  ]
+}
+`
+
+const multipleChoiceTemplate = `{
+  "kind": "question",
+  "version": "1.0",
+  "slug": "default-multiple-choice-question-slug",
+  "theme": { "slug": "default-theme" },
+  "subthemes": [ { "slug": "default-subtheme1" }, { "slug": "default-subtheme2" } ],
+  "tags": [ { "slug": "default-tag1" }, { "slug": "default-tag2" } ],
+  "qtype": "multiple_choice",
+  "difficulty": "beginner",
+  "estimated_seconds": 20,
+  "points": 2.0,
+  "shuffle_answers": true,
+  "scoring": "all_or_nothing",
+  "i18n": {
+    "fr": { "title": "Titre choix multiple", "stem": "Quelles réponses sont correctes ?", "explanation": "Explication par défaut." },
+    "en": { "title": "Multiple Choice Title", "stem": "Which answers are correct?", "explanation": "Default explanation." },
+    "es": { "title": "Título opción múltiple", "stem": "¿Qué respuestas son correctas?", "explanation": "Explicación por defecto." }
+  },
+  "answers": [
+    { "slug": "default-answer1", "is_correct": true,  "i18n": { "fr": { "label": "Réponse 1" }, "en": { "label": "Answer 1" }, "es": { "label": "Respuesta 1" } } },
+    { "slug": "default-answer2", "is_correct": true,  "i18n": { "fr": { "label": "Réponse 2" }, "en": { "label": "Answer 2" }, "es": { "label": "Respuesta 2" } } },
+    { "slug": "default-answer3", "is_correct": false, "i18n": { "fr": { "label": "Réponse 3" }, "en": { "label": "Answer 3" }, "es": { "label": "Respuesta 3" } } },
+    { "slug": "default-answer4", "is_correct": false, "i18n": { "fr": { "label": "Réponse 4" }, "en": { "label": "Answer 4" }, "es": { "label": "Respuesta 4" } } }
+  ],
+  "sources": [
+    "https://example.com/default-source"
+  ]
+}
+`
+
+const orderingTemplate = `{
+  "kind": "question",
+  "version": "1.0",
+  "slug": "default-ordering-question-slug",
+  "theme": { "slug": "default-theme" },
+  "subthemes": [ { "slug": "default-subtheme1" }, { "slug": "default-subtheme2" } ],
+  "tags": [ { "slug": "default-tag1" }, { "slug": "default-tag2" } ],
+  "qtype": "ordering",
+  "difficulty": "beginner",
+  "estimated_seconds": 25,
+  "points": 2.0,
+  "shuffle_answers": true,
+  "scoring": "partial",
+  "i18n": {
+    "fr": { "title": "Titre ordre", "stem": "Dans quel ordre ces événements ont-ils eu lieu ?", "explanation": "Explication par défaut." },
+    "en": { "title": "Ordering Title", "stem": "In what order did these events happen?", "explanation": "Default explanation." },
+    "es": { "title": "Título orden", "stem": "¿En qué orden ocurrieron estos eventos?", "explanation": "Explicación por defecto." }
+  },
+  "answers": [
+    { "slug": "default-event1", "position": 1, "i18n": { "fr": { "label": "Événement 1" }, "en": { "label": "Event 1" }, "es": { "label": "Evento 1" } } },
+    { "slug": "default-event2", "position": 2, "i18n": { "fr": { "label": "Événement 2" }, "en": { "label": "Event 2" }, "es": { "label": "Evento 2" } } },
+    { "slug": "default-event3", "position": 3, "i18n": { "fr": { "label": "Événement 3" }, "en": { "label": "Event 3" }, "es": { "label": "Evento 3" } } },
+    { "slug": "default-event4", "position": 4, "i18n": { "fr": { "label": "Événement 4" }, "en": { "label": "Event 4" }, "es": { "label": "Evento 4" } } }
+  ],
+  "sources": [
+    "https://example.com/default-source"
+  ]
+}
+`
+
+const numericTemplate = `{
+  "kind": "question",
+  "version": "1.0",
+  "slug": "default-numeric-question-slug",
+  "theme": { "slug": "default-theme" },
+  "subthemes": [ { "slug": "default-subtheme1" }, { "slug": "default-subtheme2" } ],
+  "tags": [ { "slug": "default-tag1" }, { "slug": "default-tag2" } ],
+  "qtype": "numeric",
+  "difficulty": "beginner",
+  "estimated_seconds": 20,
+  "points": 1.0,
+  "shuffle_answers": false,
+  "scoring": "partial",
+  "i18n": {
+    "fr": { "title": "Titre numérique", "stem": "Quelle est la valeur ?", "explanation": "Explication par défaut." },
+    "en": { "title": "Numeric Title", "stem": "What is the value?", "explanation": "Default explanation." },
+    "es": { "title": "Título numérico", "stem": "¿Cuál es el valor?", "explanation": "Explicación por defecto." }
+  },
+  "answers": [],
+  "numeric": { "value": 100, "unit": "km", "tolerance": 5 },
+  "sources": [
+    "https://example.com/default-source"
+  ]
+}
+`
+
+const textInputTemplate = `{
+  "kind": "question",
+  "version": "1.0",
+  "slug": "default-text-input-question-slug",
+  "theme": { "slug": "default-theme" },
+  "subthemes": [ { "slug": "default-subtheme1" }, { "slug": "default-subtheme2" } ],
+  "tags": [ { "slug": "default-tag1" }, { "slug": "default-tag2" } ],
+  "qtype": "text_input",
+  "difficulty": "beginner",
+  "estimated_seconds": 20,
+  "points": 1.0,
+  "shuffle_answers": false,
+  "typo_tolerance": 1,
+  "i18n": {
+    "fr": { "title": "Titre saisie libre", "stem": "Quelle est la réponse ?", "explanation": "Explication par défaut." },
+    "en": { "title": "Text Input Title", "stem": "What is the answer?", "explanation": "Default explanation." },
+    "es": { "title": "Título respuesta libre", "stem": "¿Cuál es la respuesta?", "explanation": "Explicación por defecto." }
+  },
+  "answers": [
+    {
+      "slug": "default-answer1",
+      "is_correct": true,
+      "i18n": { "fr": { "label": "Réponse 1" }, "en": { "label": "Answer 1" }, "es": { "label": "Respuesta 1" } },
+      "accepted": { "fr": [ "Variante 1" ], "en": [ "Variant 1" ], "es": [ "Variante 1" ] }
+    }
+  ],
+  "sources": [
+    "https://example.com/default-source"
+  ]
+}
+`
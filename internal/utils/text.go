@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Letters that are not a base letter plus combining marks, so that removing
+// the marks of the decomposed text leaves them unchanged.
+var unfoldableLetters = strings.NewReplacer(
+	"æ", "ae", "œ", "oe", "ß", "ss", "þ", "th",
+	"ð", "d", "đ", "d", "ħ", "h", "ı", "i", "ł", "l", "ŀ", "l", "ø", "o", "ŧ", "t",
+)
+
+// WordSet returns the set of the space-separated words.
+func WordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// FoldText lowercases the text and removes its accents, so that "México"
+// and "mexico" compare equal.
+func FoldText(s string) string {
+	lower := strings.ToLower(s)
+	// A transformer keeps state between calls, so each call builds its own.
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn))), lower)
+	if err != nil {
+		folded = lower
+	}
+	return unfoldableLetters.Replace(folded)
+}
+
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(FoldText(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
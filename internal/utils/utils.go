@@ -10,19 +10,33 @@ import (
 )
 
 const (
-	ManifestFile             = "datasets/general-knowledge/manifest.json"
-	QuestionsFile            = "datasets/general-knowledge/questions.ndjson"
-	ThemesFile               = "datasets/general-knowledge/themes.ndjson"
-	SubthemesFile            = "datasets/general-knowledge/subthemes.ndjson"
-	TagsFile                 = "datasets/general-knowledge/tags.ndjson"
-	NewQuestionFile          = "datasets/new-question.json"
-	NewQuestionTrueFalseFile = "datasets/new-question-true-false.json"
+	ManifestFile                  = "datasets/general-knowledge/manifest.json"
+	QuestionsFile                 = "datasets/general-knowledge/questions.ndjson"
+	ThemesFile                    = "datasets/general-knowledge/themes.ndjson"
+	SubthemesFile                 = "datasets/general-knowledge/subthemes.ndjson"
+	TagsFile                      = "datasets/general-knowledge/tags.ndjson"
+	QuestionAssetsDir             = "datasets/general-knowledge/assets"
+	NewQuestionFile               = "datasets/new-question.json"
+	NewQuestionTrueFalseFile      = "datasets/new-question-true-false.json"
+	NewQuestionMultipleChoiceFile = "datasets/new-question-multiple-choice.json"
+	NewQuestionOrderingFile       = "datasets/new-question-ordering.json"
+	NewQuestionNumericFile        = "datasets/new-question-numeric.json"
+	NewQuestionTextInputFile      = "datasets/new-question-text-input.json"
 
 	GeographyManifestFile  = "datasets/geography/manifest.json"
 	CountriesFile          = "datasets/geography/countries.ndjson"
 	ContinentsFile         = "datasets/geography/continents.ndjson"
 	RegionsFile            = "datasets/geography/regions.ndjson"
 	FlagsSVGDir            = "datasets/geography/assets/flags/svg"
+
+	QuestionSchemaFile          = "schemas/question.schema.json"
+	QuestionsManifestSchemaFile = "schemas/manifest-questions.schema.json"
+	CountrySchemaFile           = "schemas/country.schema.json"
+	ContinentSchemaFile         = "schemas/continent.schema.json"
+	RegionSchemaFile            = "schemas/region.schema.json"
+	GeographyManifestSchemaFile = "schemas/manifest-geography.schema.json"
+
+	SigningKeyEnv = "CULTPEDIA_SIGNING_KEY"
 )
 
 func LoadQuestions() ([]models.Question, error) {
@@ -108,6 +122,18 @@ func DetectModifiedTemplateFile() (filePath string, questionType string) {
 	if isTemplateModified(NewQuestionTrueFalseFile, "default-true-false-question-slug") {
 		return NewQuestionTrueFalseFile, "true_false"
 	}
+	if isTemplateModified(NewQuestionMultipleChoiceFile, "default-multiple-choice-question-slug") {
+		return NewQuestionMultipleChoiceFile, "multiple_choice"
+	}
+	if isTemplateModified(NewQuestionOrderingFile, "default-ordering-question-slug") {
+		return NewQuestionOrderingFile, "ordering"
+	}
+	if isTemplateModified(NewQuestionNumericFile, "default-numeric-question-slug") {
+		return NewQuestionNumericFile, "numeric"
+	}
+	if isTemplateModified(NewQuestionTextInputFile, "default-text-input-question-slug") {
+		return NewQuestionTextInputFile, "text_input"
+	}
 	return "", ""
 }
 
@@ -139,19 +165,30 @@ COMMANDS:
   Questions Dataset:
   validate                      Validate the questions dataset for consistency and correctness
   check-duplicates              Check for duplicate questions in the dataset
-  check-translations            Check for missing translations in the dataset
+  check-translations            Check for missing, empty or untranslated text in the dataset
+  check-similar-questions       Report pairs of near-duplicate questions (--threshold 0-1, default 0.6)
   add                           Add a new question to the dataset via interactive prompts
   sync-themes                   Synchronize themes and subthemes with the questions dataset
   bump-version                  Increment version and update manifest (automated in CI)
+                                (--sign signs the manifest with the key in $CULTPEDIA_SIGNING_KEY)
   
   Geography Dataset:
   validate-geography            Validate the geography dataset (countries, continents, regions)
+                                (--tolerance 0.01 for continent population and area totals)
   check-geography-duplicates    Check for duplicate entries in geography dataset
   check-geography-translations  Check for missing translations in geography dataset
   bump-geography-version        Increment geography version and update checksums (automated in CI)
+                                (--sign as bump-version)
+  generate-geography-questions  Generate questions from the geography dataset
+                                (--kind capital,flag,population,neighbor,population_estimate,area --count N --seed S --output FILE)
+
+  Check commands (validate, validate-geography, check-*, verify) accept --format text|json|junit|sarif
   
   General:
   init [dataset-name]        Initialize a new Cultpedia dataset structure
+  verify [manifest-path|url]    Check files and counts against a manifest (default: both datasets)
+                                (--public-key KEY also checks the manifest.json.sig signature)
+  generate-signing-key          Generate an ed25519 key pair for signing manifests
 
 CONTRIBUTION GUIDE:
   For questions: Fork → Edit template file → Use TUI to add → Create PR
@@ -225,3 +262,12 @@ func LoadRegions() ([]models.Region, error) {
 	}
 	return regions, nil
 }
+
+func Contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SignatureSuffix is appended to the manifest path to find its detached
+// signature: manifest.json is signed by manifest.json.sig.
+const SignatureSuffix = ".sig"
+
+var ErrInvalidSignature = errors.New("signature does not match the public key")
+
+func GenerateKey() (publicKey, privateKey string, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(public), base64.StdEncoding.EncodeToString(private.Seed()), nil
+}
+
+// ParsePrivateKey accepts a base64 ed25519 seed (32 bytes) or full private
+// key (64 bytes).
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("private key is not valid base64: %v", err)
+	}
+	switch len(data) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(data), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(data), nil
+	}
+	return nil, fmt.Errorf("private key must be %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(data))
+}
+
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("public key is not valid base64: %v", err)
+	}
+	if len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key must be %d bytes, got %d", ed25519.PublicKeySize, len(data))
+	}
+	return ed25519.PublicKey(data), nil
+}
+
+// Sign returns the content of the detached signature file for the exact
+// manifest bytes.
+func Sign(manifestData []byte, key ed25519.PrivateKey) []byte {
+	signature := ed25519.Sign(key, manifestData)
+	return []byte(base64.StdEncoding.EncodeToString(signature) + "\n")
+}
+
+func CheckSignature(manifestData, signatureData []byte, key ed25519.PublicKey) error {
+	signature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signatureData)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return fmt.Errorf("signature is not a base64 ed25519 signature")
+	}
+	if !ed25519.Verify(key, manifestData, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	publicKey, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	private, err := ParsePrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() returned unexpected error: %v", err)
+	}
+	public, err := ParsePublicKey(publicKey + "\n")
+	if err != nil {
+		t.Fatalf("ParsePublicKey() returned unexpected error: %v", err)
+	}
+	if !public.Equal(private.Public()) {
+		t.Error("public key does not match the private key")
+	}
+
+	full, err := ParsePrivateKey(base64.StdEncoding.EncodeToString(private))
+	if err != nil || !full.Equal(private) {
+		t.Errorf("ParsePrivateKey() should accept a full private key, got %v", err)
+	}
+
+	for _, invalid := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := ParsePrivateKey(invalid); err == nil {
+			t.Errorf("ParsePrivateKey(%q) should fail", invalid)
+		}
+		if _, err := ParsePublicKey(invalid); err == nil {
+			t.Errorf("ParsePublicKey(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestCheckSignature(t *testing.T) {
+	_, privateKey, _ := GenerateKey()
+	key, _ := ParsePrivateKey(privateKey)
+	otherPublic, _, _ := GenerateKey()
+	other, _ := ParsePublicKey(otherPublic)
+
+	data := []byte(`{"version":"1.0.0"}`)
+	signature := Sign(data, key)
+
+	if err := CheckSignature(data, signature, key.Public().(ed25519.PublicKey)); err != nil {
+		t.Errorf("CheckSignature() returned unexpected error: %v", err)
+	}
+	if err := CheckSignature([]byte(`{"version":"1.0.1"}`), signature, key.Public().(ed25519.PublicKey)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("modified manifest should fail, got %v", err)
+	}
+	if err := CheckSignature(data, signature, other); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("other key should fail, got %v", err)
+	}
+	if err := CheckSignature(data, []byte("garbage"), key.Public().(ed25519.PublicKey)); err == nil {
+		t.Error("malformed signature should fail")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	publicKey, privateKey, _ := GenerateKey()
+	key, _ := ParsePrivateKey(privateKey)
+	public, _ := ParsePublicKey(publicKey)
+
+	dir := writeDataset(t, testFiles, map[string]int{"countries": 2}, validChecksums())
+	manifestPath := filepath.Join(dir, "manifest.json")
+	verifier := &Verifier{PublicKey: public}
+
+	result, err := verifier.Verify(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Signed || len(result.Mismatches) != 1 || result.Mismatches[0].Kind != MismatchSignature {
+		t.Fatalf("unsigned manifest should be reported, got %+v", result)
+	}
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(manifestPath+SignatureSuffix, Sign(data, key), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = verifier.Verify(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Signed || !result.OK() {
+		t.Fatalf("signed manifest should verify, got %+v", result)
+	}
+
+	if err := os.WriteFile(manifestPath, append(data, ' '), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = verifier.Verify(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Signed || len(result.Mismatches) != 1 || result.Mismatches[0].Name != "manifest.json.sig" {
+		t.Errorf("modified manifest should fail the signature, got %+v", result)
+	}
+}
@@ -0,0 +1,250 @@
+// Package manifest verifies a Cultpedia dataset against the checksums and
+// counts recorded in its manifest.json.
+package manifest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	MismatchChecksum  = "checksum"
+	MismatchCount     = "count"
+	MismatchMissing   = "missing"
+	MismatchInvalid   = "invalid"
+	MismatchSignature = "signature"
+)
+
+var errNotFound = errors.New("not found")
+
+type Mismatch struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+func (m Mismatch) String() string {
+	if m.Kind == MismatchCount {
+		return "counts." + m.Name + ": " + m.Detail()
+	}
+	return m.Name + ": " + m.Detail()
+}
+
+func (m Mismatch) Detail() string {
+	switch m.Kind {
+	case MismatchChecksum:
+		return fmt.Sprintf("checksum %s does not match the manifest (%s)", m.Actual, m.Expected)
+	case MismatchCount:
+		return fmt.Sprintf("found %s, the manifest says %s", m.Actual, m.Expected)
+	case MismatchMissing:
+		return "listed in the manifest but " + m.Actual
+	default:
+		return m.Actual
+	}
+}
+
+type Result struct {
+	Location   string     `json:"location"`
+	Dataset    string     `json:"dataset"`
+	Version    string     `json:"version"`
+	Files      int        `json:"files"`
+	Signed     bool       `json:"signed"`
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+func (r *Result) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+type Verifier struct {
+	// Client fetches http(s) manifests, http.DefaultClient when nil.
+	Client *http.Client
+	// PublicKey, when set, requires a detached signature made with the
+	// matching private key next to the manifest.
+	PublicKey ed25519.PublicKey
+}
+
+// Verify reads the manifest at location, a local path or an http(s) URL, and
+// checks every file it lists. Files are resolved relative to the manifest.
+// The error is only set when the manifest itself cannot be read.
+func Verify(location string) (*Result, error) {
+	return (&Verifier{}).Verify(location)
+}
+
+func (v *Verifier) Verify(location string) (*Result, error) {
+	var read func(target string) ([]byte, error)
+	var resolve func(name string) string
+
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		base, err := url.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest url: %v", err)
+		}
+		client := v.Client
+		if client == nil {
+			client = http.DefaultClient
+		}
+		read = func(target string) ([]byte, error) {
+			return fetch(client, target)
+		}
+		resolve = func(name string) string {
+			return base.ResolveReference(&url.URL{Path: name}).String()
+		}
+	} else {
+		read = func(target string) ([]byte, error) {
+			data, err := os.ReadFile(target)
+			if os.IsNotExist(err) {
+				return nil, errNotFound
+			}
+			return data, err
+		}
+		resolve = func(name string) string {
+			return filepath.Join(filepath.Dir(location), filepath.FromSlash(name))
+		}
+	}
+
+	manifestData, err := read(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %v", err)
+	}
+
+	var m struct {
+		Dataset   string            `json:"dataset"`
+		Version   string            `json:"version"`
+		Counts    map[string]int    `json:"counts"`
+		Checksums map[string]string `json:"checksums"`
+	}
+	if err := json.Unmarshal(manifestData, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %v", err)
+	}
+
+	result := &Result{Location: location, Dataset: m.Dataset, Version: m.Version, Mismatches: []Mismatch{}}
+
+	if v.PublicKey != nil {
+		name := path.Base(location) + SignatureSuffix
+		signature, err := read(location + SignatureSuffix)
+		switch {
+		case errors.Is(err, errNotFound):
+			result.Mismatches = append(result.Mismatches, Mismatch{Kind: MismatchSignature, Name: name, Actual: "the manifest is not signed"})
+		case err != nil:
+			result.Mismatches = append(result.Mismatches, Mismatch{Kind: MismatchSignature, Name: name, Actual: err.Error()})
+		default:
+			if err := CheckSignature(manifestData, signature, v.PublicKey); err != nil {
+				result.Mismatches = append(result.Mismatches, Mismatch{Kind: MismatchSignature, Name: name, Actual: err.Error()})
+			} else {
+				result.Signed = true
+			}
+		}
+	}
+	lines := make(map[string]int)
+
+	names := make([]string, 0, len(m.Checksums))
+	for name := range m.Checksums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !fs.ValidPath(name) {
+			result.Mismatches = append(result.Mismatches, Mismatch{Kind: MismatchInvalid, Name: name, Actual: "file name must be a relative path inside the dataset"})
+			continue
+		}
+		data, err := read(resolve(name))
+		if errors.Is(err, errNotFound) {
+			result.Mismatches = append(result.Mismatches, Mismatch{Kind: MismatchMissing, Name: name, Actual: "the file does not exist"})
+			continue
+		}
+		if err != nil {
+			result.Mismatches = append(result.Mismatches, Mismatch{Kind: MismatchMissing, Name: name, Actual: err.Error()})
+			continue
+		}
+
+		result.Files++
+		lines[name] = countLines(data)
+		sum := sha256.Sum256(data)
+		if actual := "sha256-" + hex.EncodeToString(sum[:]); actual != m.Checksums[name] {
+			result.Mismatches = append(result.Mismatches, Mismatch{Kind: MismatchChecksum, Name: name, Expected: m.Checksums[name], Actual: actual})
+		}
+	}
+
+	keys := make([]string, 0, len(m.Counts))
+	for key := range m.Counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		actual, ok := count(key, m.Checksums, lines)
+		if ok && actual != m.Counts[key] {
+			result.Mismatches = append(result.Mismatches, Mismatch{Kind: MismatchCount, Name: key, Expected: strconv.Itoa(m.Counts[key]), Actual: strconv.Itoa(actual)})
+		}
+	}
+
+	return result, nil
+}
+
+// count returns the number of records behind a manifest count: the lines of
+// the matching NDJSON file, the listed SVG files for "flags" or the listed
+// assets for "media". Counts with nothing to check them against are skipped.
+func count(key string, checksums map[string]string, lines map[string]int) (int, bool) {
+	var match func(name string) bool
+	switch key {
+	case "flags":
+		match = func(name string) bool { return strings.HasSuffix(name, ".svg") }
+	case "media":
+		match = func(name string) bool { return strings.HasPrefix(name, "assets/") }
+	}
+	if match != nil {
+		n := 0
+		for name := range checksums {
+			if match(name) {
+				n++
+			}
+		}
+		return n, n > 0
+	}
+	n, ok := lines[key+".ndjson"]
+	return n, ok
+}
+
+func countLines(data []byte) int {
+	n := 0
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func fetch(client *http.Client, target string) ([]byte, error) {
+	resp, err := client.Get(target)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,157 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func checksum(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return "sha256-" + hex.EncodeToString(sum[:])
+}
+
+func writeDataset(t *testing.T, files map[string]string, counts map[string]int, checksums map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"dataset":   "geography",
+		"version":   "1.2.3",
+		"counts":    counts,
+		"checksums": checksums,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+var testFiles = map[string]string{
+	"countries.ndjson":        "{\"slug\":\"fr\"}\n{\"slug\":\"be\"}\n\n",
+	"regions.ndjson":          "{\"slug\":\"western_europe\"}\n",
+	"assets/flags/svg/fr.svg": "<svg/>",
+	"assets/flags/svg/be.svg": "<svg></svg>",
+}
+
+func validChecksums() map[string]string {
+	checksums := make(map[string]string)
+	for name, content := range testFiles {
+		checksums[name] = checksum(content)
+	}
+	return checksums
+}
+
+func TestVerify(t *testing.T) {
+	counts := map[string]int{"countries": 2, "regions": 1, "flags": 2, "continents": 6}
+	dir := writeDataset(t, testFiles, counts, validChecksums())
+
+	result, err := Verify(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatalf("Verify() returned unexpected error: %v", err)
+	}
+	if !result.OK() || result.Files != 4 || result.Version != "1.2.3" || result.Dataset != "geography" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	checksums := validChecksums()
+	checksums["missing.ndjson"] = checksum("")
+	checksums["../outside.ndjson"] = checksum("")
+	counts["countries"] = 3
+	files := map[string]string{
+		"countries.ndjson":        testFiles["countries.ndjson"],
+		"regions.ndjson":          "{\"slug\":\"western_europe\"}\n{\"slug\":\"northern_europe\"}\n",
+		"assets/flags/svg/fr.svg": testFiles["assets/flags/svg/fr.svg"],
+		"assets/flags/svg/be.svg": testFiles["assets/flags/svg/be.svg"],
+	}
+	dir = writeDataset(t, files, counts, checksums)
+
+	result, err = Verify(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatalf("Verify() returned unexpected error: %v", err)
+	}
+	var kinds []string
+	for _, m := range result.Mismatches {
+		kinds = append(kinds, m.Kind+" "+m.Name)
+	}
+	expected := []string{
+		"invalid ../outside.ndjson",
+		"missing missing.ndjson",
+		"checksum regions.ndjson",
+		"count countries",
+		"count regions",
+	}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("mismatches = %v, expected %v", kinds, expected)
+	}
+	if got := result.Mismatches[3].String(); got != "counts.countries: found 2, the manifest says 3" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if _, err := Verify(filepath.Join(t.TempDir(), "manifest.json")); err == nil {
+		t.Error("expected an error for a missing manifest")
+	}
+}
+
+func TestVerifyURL(t *testing.T) {
+	checksums := validChecksums()
+	checksums["themes.ndjson"] = checksum("")
+	dir := writeDataset(t, testFiles, map[string]int{"countries": 2, "flags": 2}, checksums)
+
+	server := httptest.NewServer(http.StripPrefix("/datasets/geography/", http.FileServer(http.Dir(dir))))
+	defer server.Close()
+
+	result, err := (&Verifier{Client: server.Client()}).Verify(server.URL + "/datasets/geography/manifest.json")
+	if err != nil {
+		t.Fatalf("Verify() returned unexpected error: %v", err)
+	}
+	if result.Files != 4 || len(result.Mismatches) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if m := result.Mismatches[0]; m.Kind != MismatchMissing || m.Name != "themes.ndjson" {
+		t.Errorf("unexpected mismatch: %+v", m)
+	}
+
+	if _, err := (&Verifier{Client: server.Client()}).Verify(server.URL + "/other/manifest.json"); err == nil {
+		t.Error("expected an error for a manifest that cannot be fetched")
+	}
+}
+
+func TestVerifyMediaCount(t *testing.T) {
+	files := map[string]string{
+		"questions.ndjson":     "{\"slug\":\"q1\"}\n",
+		"assets/mona-lisa.jpg": "jpeg",
+		"assets/anthem.mp3":    "mp3",
+	}
+	checksums := make(map[string]string)
+	for name, content := range files {
+		checksums[name] = checksum(content)
+	}
+	dir := writeDataset(t, files, map[string]int{"questions": 1, "media": 3}, checksums)
+
+	result, err := Verify(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatalf("Verify() returned unexpected error: %v", err)
+	}
+	if len(result.Mismatches) != 1 || result.Mismatches[0].String() != "counts.media: found 2, the manifest says 3" {
+		t.Errorf("unexpected mismatches: %+v", result.Mismatches)
+	}
+}
@@ -0,0 +1,80 @@
+// Package textmatch compares free-text answers with their accepted variants,
+// ignoring case, accents, punctuation and leading articles.
+package textmatch
+
+import (
+	"strings"
+
+	"cultpedia/internal/utils"
+)
+
+// CharsPerTypo limits typo tolerance on short variants: a variant accepts at
+// most one edit per CharsPerTypo characters, so "Rome" never matches "Home".
+const CharsPerTypo = 5
+
+var articles = map[string]map[string]bool{
+	"en": utils.WordSet("the a an"),
+	"fr": utils.WordSet("le la les l un une des"),
+	"es": utils.WordSet("el la los las lo un una unos unas"),
+}
+
+// Normalize lowercases s, removes accents and punctuation, and drops a
+// leading article of lang: "L'Élysée" and "elysee" both give "elysee".
+func Normalize(s, lang string) string {
+	tokens := utils.Tokenize(s)
+	if len(tokens) > 1 && articles[lang][tokens[0]] {
+		tokens = tokens[1:]
+	}
+	return strings.Join(tokens, " ")
+}
+
+// Distance is the Levenshtein distance between a and b, counted in runes.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// Match returns the variant that input stands for. Variants are compared
+// after Normalize; an exact match wins, otherwise the closest variant within
+// maxTypos edits is returned.
+func Match(input string, variants []string, lang string, maxTypos int) (string, bool) {
+	normalized := Normalize(input, lang)
+	if normalized == "" {
+		return "", false
+	}
+
+	best, bestDistance := "", -1
+	for _, variant := range variants {
+		target := Normalize(variant, lang)
+		if target == "" {
+			continue
+		}
+		if target == normalized {
+			return variant, true
+		}
+		allowed := min(maxTypos, len([]rune(target))/CharsPerTypo)
+		if allowed == 0 {
+			continue
+		}
+		if d := Distance(normalized, target); d <= allowed && (bestDistance < 0 || d < bestDistance) {
+			best, bestDistance = variant, d
+		}
+	}
+	return best, bestDistance >= 0
+}
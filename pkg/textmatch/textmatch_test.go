@@ -0,0 +1,77 @@
+package textmatch
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input    string
+		lang     string
+		expected string
+	}{
+		{"L'Élysée", "fr", "elysee"},
+		{"  The Beatles ", "en", "beatles"},
+		{"El Niño", "es", "nino"},
+		{"Saint-Étienne", "fr", "saint etienne"},
+		{"The", "en", "the"},
+		{"La Paz", "en", "la paz"},
+		{"", "en", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.input, tt.lang); got != tt.expected {
+			t.Errorf("Normalize(%q, %s) = %q, expected %q", tt.input, tt.lang, got, tt.expected)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"paris", "paris", 0},
+		{"paris", "pari", 1},
+		{"kitten", "sitting", 3},
+		{"é", "e", 1},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Distance(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	variants := []string{"Léonard de Vinci", "Vinci", "Leonardo"}
+
+	tests := []struct {
+		input    string
+		maxTypos int
+		expected string
+		ok       bool
+	}{
+		{"leonard de vinci", 0, "Léonard de Vinci", true},
+		{"LÉONARD DE VINCI !", 0, "Léonard de Vinci", true},
+		{"Leonard de Vinchi", 0, "", false},
+		{"Leonard de Vinchi", 1, "Léonard de Vinci", true},
+		{"Leonard da Vinchi", 1, "", false},
+		{"Leonard da Vinchi", 2, "Léonard de Vinci", true},
+		{"Vinco", 2, "Vinci", true},
+		{"Leonardo", 2, "Leonardo", true},
+		{"", 2, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Match(tt.input, variants, "fr", tt.maxTypos)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("Match(%q, %d) = %q, %v, expected %q, %v", tt.input, tt.maxTypos, got, ok, tt.expected, tt.ok)
+		}
+	}
+
+	if _, ok := Match("Home", []string{"Rome"}, "en", 2); ok {
+		t.Error("short variants should not accept typos")
+	}
+	if got, ok := Match("the Netherlands", []string{"Netherlands"}, "en", 0); !ok || got != "Netherlands" {
+		t.Errorf("leading article should be ignored, got %q, %v", got, ok)
+	}
+}